@@ -0,0 +1,81 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"railway-api-uptime-monitor/internal/jsonpath"
+)
+
+// Assertion types supported on HTTP responses.
+const (
+	AssertBodyContains    = "body_contains"
+	AssertBodyRegex       = "body_regex"
+	AssertJSONPathEquals  = "jsonpath_equals"
+	AssertJSONPathExists  = "jsonpath_exists"
+	AssertJSONPathCompare = "jsonpath_compare"
+	AssertHeaderEquals    = "header_equals"
+)
+
+var compareOperators = map[string]bool{
+	"eq": true, "ne": true, "lt": true, "lte": true, "gt": true, "gte": true,
+}
+
+// Assertion is a check run against the response of a monitor. Path is the
+// JSONPath for jsonpath_* assertions and the header name for header_equals.
+type Assertion struct {
+	Type     string `json:"type" bson:"type"`
+	Path     string `json:"path,omitempty" bson:"path,omitempty"`
+	Operator string `json:"operator,omitempty" bson:"operator,omitempty"`
+	Value    string `json:"value,omitempty" bson:"value,omitempty"`
+}
+
+func (a Assertion) String() string {
+	switch a.Type {
+	case AssertBodyContains, AssertBodyRegex:
+		return fmt.Sprintf("%s %q", a.Type, a.Value)
+	case AssertJSONPathExists:
+		return fmt.Sprintf("%s %s", a.Type, a.Path)
+	case AssertJSONPathCompare:
+		return fmt.Sprintf("%s %s %s %s", a.Type, a.Path, a.Operator, a.Value)
+	default:
+		return fmt.Sprintf("%s %s == %q", a.Type, a.Path, a.Value)
+	}
+}
+
+func (a Assertion) Validate() error {
+	switch a.Type {
+	case AssertBodyContains:
+		if a.Value == "" {
+			return errors.New("value is required")
+		}
+	case AssertBodyRegex:
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return fmt.Errorf("invalid regex: %v", err)
+		}
+	case AssertJSONPathEquals, AssertJSONPathExists:
+		if err := jsonpath.Compile(a.Path); err != nil || a.Path == "" {
+			return fmt.Errorf("invalid path %q", a.Path)
+		}
+	case AssertJSONPathCompare:
+		if err := jsonpath.Compile(a.Path); err != nil || a.Path == "" {
+			return fmt.Errorf("invalid path %q", a.Path)
+		}
+		if !compareOperators[a.Operator] {
+			return fmt.Errorf("operator must be one of eq, ne, lt, lte, gt, gte, got %q", a.Operator)
+		}
+		if _, err := strconv.ParseFloat(a.Value, 64); err != nil {
+			return fmt.Errorf("value must be a number, got %q", a.Value)
+		}
+	case AssertHeaderEquals:
+		if a.Path == "" {
+			return errors.New("path must name the header")
+		}
+	default:
+		return fmt.Errorf("unknown assertion type %q", a.Type)
+	}
+
+	return nil
+}
@@ -1,32 +1,191 @@
 package config
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	Port              string
-	MongoURI          string
-	DatabaseName      string
-	CheckInterval     string
-	TimeoutSeconds    int
-	MaxRetries        int
-	SlackWebhookURL   string
-	DiscordWebhookURL string
-	EnableSlack       bool
-	EnableDiscord     bool
-	DowntimeThreshold int
+	Port                 string
+	MongoURI             string
+	DatabaseName         string
+	CheckInterval        string
+	TimeoutSeconds       int
+	MaxRetries           int
+	SlackWebhookURL      string
+	DiscordWebhookURL    string
+	EnableSlack          bool
+	EnableDiscord        bool
+	EnableWebhook        bool
+	WebhookURL           string
+	WebhookMethod        string
+	WebhookHeaders       map[string]string
+	WebhookTemplate      string
+	WebhookTemplateFile  string
+	WebhookSecret        string
+	ChannelsFile         string
+	DowntimeThreshold    int
+	MonitorsSeedFile     string
+	ReloadSeconds        int
+	MaxJitterSeconds     int
+	MaxConcurrent        int
+	RetryBackoffMs       int
+	CertExpiryDays       []int
+	AlertReminderMinutes int
 }
 
 type APIConfig struct {
-	Name           string `json:"name"`
-	URL            string `json:"url"`
-	Method         string `json:"method"`
-	ExpectedStatus int    `json:"expected_status"`
-	Timeout        int    `json:"timeout"`
+	Name             string            `json:"name" bson:"name"`
+	Type             string            `json:"type,omitempty" bson:"type,omitempty"` // "http" (default), "tcp", "dns", "ping", "grpc", "websocket", "push", "steps"
+	URL              string            `json:"url" bson:"url"`
+	Method           string            `json:"method" bson:"method"`
+	Headers          map[string]string `json:"headers,omitempty" bson:"headers,omitempty"`
+	Query            map[string]string `json:"query,omitempty" bson:"query,omitempty"`
+	Body             string            `json:"body,omitempty" bson:"body,omitempty"`
+	ContentType      string            `json:"content_type,omitempty" bson:"content_type,omitempty"`
+	ExpectedStatus   int               `json:"expected_status" bson:"expected_status"`
+	ExpectedStatuses string            `json:"expected_statuses,omitempty" bson:"expected_statuses,omitempty"` // e.g. "200-299,401"; overrides ExpectedStatus
+	Timeout          int               `json:"timeout" bson:"timeout"`
+	Interval         string            `json:"interval,omitempty" bson:"interval,omitempty"`
+	Retries          *int              `json:"retries,omitempty" bson:"retries,omitempty"`
+	RetryBackoff     string            `json:"retry_backoff,omitempty" bson:"retry_backoff,omitempty"`
+	CertExpiryDays   []int             `json:"cert_expiry_days,omitempty" bson:"cert_expiry_days,omitempty"`
+	ReminderInterval string            `json:"reminder_interval,omitempty" bson:"reminder_interval,omitempty"` // "0s" disables reminders
+	Notify           []NotifyRule      `json:"notify,omitempty" bson:"notify,omitempty"`                       // empty notifies every channel
+	Assertions       []Assertion       `json:"assertions,omitempty" bson:"assertions,omitempty"`
+	Content          *ContentConfig    `json:"content,omitempty" bson:"content,omitempty"`
+	SLO              *SLOConfig        `json:"slo,omitempty" bson:"slo,omitempty"`
+	TCP              *TCPConfig        `json:"tcp,omitempty" bson:"tcp,omitempty"`
+	DNS              *DNSConfig        `json:"dns,omitempty" bson:"dns,omitempty"`
+	Ping             *PingConfig       `json:"ping,omitempty" bson:"ping,omitempty"`
+	GRPC             *GRPCConfig       `json:"grpc,omitempty" bson:"grpc,omitempty"`
+	WebSocket        *WebSocketConfig  `json:"websocket,omitempty" bson:"websocket,omitempty"`
+	Push             *PushConfig       `json:"push,omitempty" bson:"push,omitempty"`
+	Steps            []StepConfig      `json:"steps,omitempty" bson:"steps,omitempty"`
+	CreatedAt        time.Time         `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt        time.Time         `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// SLOConfig sets response time thresholds. Successful checks at or above
+// either threshold are reported as "degraded" instead of "up".
+type SLOConfig struct {
+	Warning  string `json:"warning,omitempty" bson:"warning,omitempty"`
+	Critical string `json:"critical,omitempty" bson:"critical,omitempty"`
+}
+
+// Thresholds returns the parsed thresholds; an unset threshold is zero.
+func (s SLOConfig) Thresholds() (warning, critical time.Duration, err error) {
+	if s.Warning != "" {
+		if warning, err = time.ParseDuration(s.Warning); err != nil || warning <= 0 {
+			return 0, 0, fmt.Errorf("invalid slo.warning %q", s.Warning)
+		}
+	}
+	if s.Critical != "" {
+		if critical, err = time.ParseDuration(s.Critical); err != nil || critical <= 0 {
+			return 0, 0, fmt.Errorf("invalid slo.critical %q", s.Critical)
+		}
+	}
+	return warning, critical, nil
+}
+
+// TCPConfig holds the options of "tcp" monitors, whose URL is "host:port".
+type TCPConfig struct {
+	// Send is written to the connection once it is established.
+	Send string `json:"send,omitempty" bson:"send,omitempty"`
+	// Expect is a regular expression the response must match.
+	Expect string `json:"expect,omitempty" bson:"expect,omitempty"`
+	// TLS wraps the connection in TLS before sending anything.
+	TLS bool `json:"tls,omitempty" bson:"tls,omitempty"`
+}
+
+// DNSConfig holds the options of "dns" monitors, whose URL is the name to
+// resolve.
+type DNSConfig struct {
+	// RecordType is one of A, AAAA, CNAME, MX or TXT.
+	RecordType string `json:"record_type,omitempty" bson:"record_type,omitempty"`
+	// Resolver is the "host[:port]" of the DNS server to query. The system
+	// resolver is used when empty.
+	Resolver string `json:"resolver,omitempty" bson:"resolver,omitempty"`
+	// Expected lists values that must all be present in the answer.
+	Expected []string `json:"expected,omitempty" bson:"expected,omitempty"`
+}
+
+// PingConfig holds the options of "ping" monitors, which measure
+// reachability of "host:port" with several TCP connect or UDP echo probes.
+type PingConfig struct {
+	// Protocol is "tcp" (default) or "udp".
+	Protocol string `json:"protocol,omitempty" bson:"protocol,omitempty"`
+	// Count is the number of probes sent per check.
+	Count int `json:"count,omitempty" bson:"count,omitempty"`
+	// ProbeTimeout bounds each probe, e.g. "1s".
+	ProbeTimeout string `json:"probe_timeout,omitempty" bson:"probe_timeout,omitempty"`
+	// MaxLossPercent is the highest loss still considered up.
+	MaxLossPercent *float64 `json:"max_loss_percent,omitempty" bson:"max_loss_percent,omitempty"`
+	// Payload is sent by UDP probes; the target is expected to reply.
+	Payload string `json:"payload,omitempty" bson:"payload,omitempty"`
+}
+
+// GRPCConfig holds the options of "grpc" monitors, which call the standard
+// grpc.health.v1.Health/Check method on "host:port".
+type GRPCConfig struct {
+	// Service is the service name sent in the health request; empty asks
+	// about the server as a whole.
+	Service string `json:"service,omitempty" bson:"service,omitempty"`
+	// TLS connects with TLS instead of plaintext.
+	TLS bool `json:"tls,omitempty" bson:"tls,omitempty"`
+	// Metadata is sent as request headers.
+	Metadata map[string]string `json:"metadata,omitempty" bson:"metadata,omitempty"`
+}
+
+// WebSocketConfig holds the options of "websocket" monitors, whose URL uses
+// the ws or wss scheme. Headers are sent with the upgrade request.
+type WebSocketConfig struct {
+	// Send is written as a text message once the connection is upgraded.
+	Send string `json:"send,omitempty" bson:"send,omitempty"`
+	// Expect is a regular expression a reply must match. Without it any
+	// reply to Send is accepted.
+	Expect string `json:"expect,omitempty" bson:"expect,omitempty"`
+}
+
+// PushConfig holds the options of "push" monitors, which are not polled but
+// expect a heartbeat on /api/heartbeat/<token> at least once per period.
+type PushConfig struct {
+	// Token identifies the monitor in its heartbeat URL. It is generated
+	// when left empty.
+	Token string `json:"token,omitempty" bson:"token,omitempty"`
+	// Period is the expected time between heartbeats.
+	Period string `json:"period,omitempty" bson:"period,omitempty"`
+	// Grace is added to Period before a missing heartbeat counts as down.
+	Grace string `json:"grace,omitempty" bson:"grace,omitempty"`
+}
+
+// Deadline returns how long the monitor may go without a heartbeat.
+func (p PushConfig) Deadline() (time.Duration, error) {
+	period, err := time.ParseDuration(p.Period)
+	if err != nil {
+		return 0, fmt.Errorf("invalid push.period %q", p.Period)
+	}
+	grace, err := time.ParseDuration(p.Grace)
+	if err != nil {
+		return 0, fmt.Errorf("invalid push.grace %q", p.Grace)
+	}
+	return period + grace, nil
+}
+
+// NewPushToken returns a random heartbeat token.
+func NewPushToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
 }
 
 type APIsConfig struct {
@@ -35,42 +194,42 @@ type APIsConfig struct {
 
 func Load() *Config {
 	return &Config{
-		Port:              getEnv("PORT", "8080"),
-		MongoURI:          getEnv("MONGODB_URI", "mongodb://localhost:27017"),
-		DatabaseName:      getEnv("DATABASE_NAME", "uptime_monitor"),
-		CheckInterval:     getEnv("CHECK_INTERVAL", "*/5 * * * *"),
-		TimeoutSeconds:    getEnvAsInt("TIMEOUT_SECONDS", 30),
-		MaxRetries:        getEnvAsInt("MAX_RETRIES", 3),
-		SlackWebhookURL:   getEnv("SLACK_WEBHOOK_URL", ""),
-		DiscordWebhookURL: getEnv("DISCORD_WEBHOOK_URL", ""),
-		EnableSlack:       getEnvAsBool("ENABLE_SLACK", false),
-		EnableDiscord:     getEnvAsBool("ENABLE_DISCORD", false),
-		DowntimeThreshold: getEnvAsInt("DOWNTIME_THRESHOLD", 3),
+		Port:                 getEnv("PORT", "8080"),
+		MongoURI:             getEnv("MONGODB_URI", "mongodb://localhost:27017"),
+		DatabaseName:         getEnv("DATABASE_NAME", "uptime_monitor"),
+		CheckInterval:        getEnv("CHECK_INTERVAL", "*/5 * * * *"),
+		TimeoutSeconds:       getEnvAsInt("TIMEOUT_SECONDS", 30),
+		MaxRetries:           getEnvAsInt("MAX_RETRIES", 3),
+		SlackWebhookURL:      getEnv("SLACK_WEBHOOK_URL", ""),
+		DiscordWebhookURL:    getEnv("DISCORD_WEBHOOK_URL", ""),
+		EnableSlack:          getEnvAsBool("ENABLE_SLACK", false),
+		EnableDiscord:        getEnvAsBool("ENABLE_DISCORD", false),
+		EnableWebhook:        getEnvAsBool("ENABLE_WEBHOOK", false),
+		WebhookURL:           getEnv("WEBHOOK_URL", ""),
+		WebhookMethod:        getEnv("WEBHOOK_METHOD", "POST"),
+		WebhookHeaders:       getEnvAsMap("WEBHOOK_HEADERS"),
+		WebhookTemplate:      getEnv("WEBHOOK_TEMPLATE", ""),
+		WebhookTemplateFile:  getEnv("WEBHOOK_TEMPLATE_FILE", ""),
+		WebhookSecret:        getEnv("WEBHOOK_SECRET", ""),
+		ChannelsFile:         getEnv("NOTIFICATION_CHANNELS_FILE", "config/channels.json"),
+		DowntimeThreshold:    getEnvAsInt("DOWNTIME_THRESHOLD", 3),
+		MonitorsSeedFile:     getEnv("MONITORS_SEED_FILE", "config/apis.json"),
+		ReloadSeconds:        getEnvAsInt("MONITOR_RELOAD_SECONDS", 30),
+		MaxJitterSeconds:     getEnvAsInt("MAX_JITTER_SECONDS", 10),
+		MaxConcurrent:        getEnvAsInt("MAX_CONCURRENT_CHECKS", 10),
+		RetryBackoffMs:       getEnvAsInt("RETRY_BACKOFF_MS", 1000),
+		CertExpiryDays:       getEnvAsIntList("CERT_EXPIRY_DAYS", []int{30, 14, 3}),
+		AlertReminderMinutes: getEnvAsInt("ALERT_REMINDER_MINUTES", 60),
 	}
 }
 
-func LoadAPIs() (*APIsConfig, error) {
-	file, err := os.ReadFile("config/apis.json")
+// LoadAPIs reads monitor definitions from a JSON file. Monitors live in the
+// database; the file is only used to seed it, so a missing file is reported
+// to the caller rather than replaced with defaults.
+func LoadAPIs(path string) (*APIsConfig, error) {
+	file, err := os.ReadFile(path)
 	if err != nil {
-		// Create default config if file doesn't exist
-		defaultConfig := &APIsConfig{
-			APIs: []APIConfig{
-				{
-					Name:           "Example API",
-					URL:            "https://httpbin.org/status/200",
-					Method:         "GET",
-					ExpectedStatus: 200,
-					Timeout:        30,
-				},
-			},
-		}
-
-		// Create config directory and file
-		os.MkdirAll("config", 0755)
-		defaultJSON, _ := json.MarshalIndent(defaultConfig, "", "  ")
-		os.WriteFile("config/apis.json", defaultJSON, 0644)
-
-		return defaultConfig, nil
+		return nil, err
 	}
 
 	var config APIsConfig
@@ -98,6 +257,40 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsIntList(key string, defaultValue []int) []int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var values []int
+	for _, part := range strings.Split(value, ",") {
+		intValue, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			log.Printf("Invalid integer list for %s: %s, using default: %v", key, value, defaultValue)
+			return defaultValue
+		}
+		values = append(values, intValue)
+	}
+	return values
+}
+
+// getEnvAsMap reads a JSON object of strings, such as
+// {"Authorization": "Bearer abc"}.
+func getEnvAsMap(key string) map[string]string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal([]byte(value), &values); err != nil {
+		log.Printf("Invalid JSON object for %s: %v, ignoring it", key, err)
+		return nil
+	}
+	return values
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"railway-api-uptime-monitor/internal/jsonpath"
+
+	"github.com/andybalholm/cascadia"
+)
+
+// ContentConfig enables content tracking on an HTTP monitor. The response
+// body, or the part of it picked by Selector or JSONPath, is normalized and
+// hashed on each successful check.
+type ContentConfig struct {
+	// Selector is a CSS selector; the text of the matching elements is
+	// tracked.
+	Selector string `json:"selector,omitempty" bson:"selector,omitempty"`
+	// JSONPath picks the tracked value from a JSON body.
+	JSONPath string `json:"jsonpath,omitempty" bson:"jsonpath,omitempty"`
+	// TrackChanges alerts whenever the tracked content changes.
+	TrackChanges bool `json:"track_changes,omitempty" bson:"track_changes,omitempty"`
+	// Keywords must all appear in the tracked content.
+	Keywords []string `json:"keywords,omitempty" bson:"keywords,omitempty"`
+	// ForbiddenKeywords must not appear in the tracked content.
+	ForbiddenKeywords []string `json:"forbidden_keywords,omitempty" bson:"forbidden_keywords,omitempty"`
+}
+
+func (c ContentConfig) Validate() error {
+	if c.Selector != "" && c.JSONPath != "" {
+		return errors.New("content.selector and content.jsonpath are mutually exclusive")
+	}
+	if c.Selector != "" {
+		if _, err := cascadia.Compile(c.Selector); err != nil {
+			return fmt.Errorf("invalid content.selector: %v", err)
+		}
+	}
+	if c.JSONPath != "" {
+		if err := jsonpath.Compile(c.JSONPath); err != nil {
+			return fmt.Errorf("invalid content.jsonpath %q", c.JSONPath)
+		}
+	}
+	for _, keyword := range append(append([]string(nil), c.Keywords...), c.ForbiddenKeywords...) {
+		if keyword == "" {
+			return errors.New("content keywords must not be empty")
+		}
+	}
+	if !c.TrackChanges && len(c.Keywords) == 0 && len(c.ForbiddenKeywords) == 0 {
+		return errors.New("content needs track_changes, keywords or forbidden_keywords")
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// MinInterval is the shortest check interval a monitor may use.
+const MinInterval = 5 * time.Second
+
+// ParseInterval accepts either a Go duration ("30s", "10m") or a standard
+// five-field cron expression ("*/5 * * * *").
+func ParseInterval(interval string) (cron.Schedule, error) {
+	if d, err := time.ParseDuration(interval); err == nil {
+		if d < MinInterval {
+			return nil, fmt.Errorf("interval %s is shorter than the minimum of %s", d, MinInterval)
+		}
+		return cron.Every(d), nil
+	}
+
+	schedule, err := cron.ParseStandard(interval)
+	if err != nil {
+		return nil, fmt.Errorf("interval %q is neither a duration nor a cron expression: %v", interval, err)
+	}
+
+	return schedule, nil
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Notification channel types.
+const (
+	ChannelSlack   = "slack"
+	ChannelDiscord = "discord"
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+)
+
+// Connection security of email channels.
+const (
+	SMTPStartTLS = "starttls"
+	SMTPTLS      = "tls"
+	SMTPNone     = "none"
+)
+
+// Severities lists the alert severities, from least to most severe.
+var Severities = []string{"info", "warning", "critical"}
+
+// AlertTypes lists the alert types notification rules can filter on.
+// Recovery ("up") and manual resolve ("incident_resolved") notifications
+// follow the alert they resolve.
+var AlertTypes = []string{"down", "timeout", "degraded", "certificate", "dns_change", "content_change", "keyword"}
+
+// ChannelConfig defines a named notification channel. Method, Headers and
+// Secret only apply to webhook channels, and Host through HTMLTemplate to
+// email channels. Template and TemplateFile hold the webhook body or the
+// plaintext email body.
+type ChannelConfig struct {
+	Name         string            `json:"name"`
+	Type         string            `json:"type"` // "slack", "discord", "webhook" or "email"
+	URL          string            `json:"url,omitempty"`
+	Method       string            `json:"method,omitempty"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	Template     string            `json:"template,omitempty"`
+	TemplateFile string            `json:"template_file,omitempty"`
+	Secret       string            `json:"secret,omitempty"`
+
+	Host         string   `json:"host,omitempty"`
+	Port         int      `json:"port,omitempty"`
+	Security     string   `json:"security,omitempty"` // "starttls" (default), "tls" or "none"
+	Username     string   `json:"username,omitempty"`
+	Password     string   `json:"password,omitempty"`
+	From         string   `json:"from,omitempty"`
+	To           []string `json:"to,omitempty"`
+	Subject      string   `json:"subject,omitempty"`
+	HTMLTemplate string   `json:"html_template,omitempty"`
+}
+
+type ChannelsConfig struct {
+	Channels []ChannelConfig `json:"channels"`
+}
+
+// NotifyRule sends a monitor's notifications to a channel. Empty filters
+// match every severity or alert type.
+type NotifyRule struct {
+	Channel    string   `json:"channel" bson:"channel"`
+	Severities []string `json:"severities,omitempty" bson:"severities,omitempty"`
+	Types      []string `json:"types,omitempty" bson:"types,omitempty"`
+}
+
+func (r NotifyRule) Validate() error {
+	if r.Channel == "" {
+		return errors.New("channel is required")
+	}
+	for _, severity := range r.Severities {
+		if !contains(Severities, severity) {
+			return fmt.Errorf("unknown severity %q", severity)
+		}
+	}
+	for _, alertType := range r.Types {
+		if !contains(AlertTypes, alertType) {
+			return fmt.Errorf("unknown alert type %q", alertType)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the rule selects an alert of the given type and
+// severity.
+func (r NotifyRule) Matches(alertType, severity string) bool {
+	if len(r.Severities) > 0 && !contains(r.Severities, severity) {
+		return false
+	}
+	if len(r.Types) > 0 && !contains(r.Types, alertType) {
+		return false
+	}
+	return true
+}
+
+// ResolveSecrets returns a copy of the channel with the ${env:NAME} and
+// ${file:/path} placeholders in its URL, headers, secret and credentials
+// replaced by their values.
+func (c ChannelConfig) ResolveSecrets() (ChannelConfig, error) {
+	secrets := &Secrets{}
+	resolved := c
+
+	var err error
+	if resolved.URL, err = secrets.resolve(c.URL); err != nil {
+		return c, err
+	}
+	if resolved.Headers, err = secrets.resolveMap(c.Headers); err != nil {
+		return c, err
+	}
+	if resolved.Secret, err = secrets.resolve(c.Secret); err != nil {
+		return c, err
+	}
+	if resolved.Username, err = secrets.resolve(c.Username); err != nil {
+		return c, err
+	}
+	if resolved.Password, err = secrets.resolve(c.Password); err != nil {
+		return c, err
+	}
+
+	return resolved, nil
+}
+
+// LoadChannels reads notification channel definitions from a JSON file.
+func LoadChannels(path string) (*ChannelsConfig, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config ChannelsConfig
+	if err := json.Unmarshal(file, &config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
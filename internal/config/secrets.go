@@ -0,0 +1,291 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// secretRef matches ${env:NAME} and ${file:/path} placeholders.
+var secretRef = regexp.MustCompile(`\$\{(env|file):([^}]+)\}`)
+
+const redacted = "[REDACTED]"
+
+// SecretEnvPrefix is the prefix of the environment variables ${env:NAME}
+// placeholders may read. Monitors are defined through an unauthenticated
+// API, so they must not reach the service's own settings such as
+// MONGODB_URI.
+const SecretEnvPrefix = "MONITOR_SECRET_"
+
+// defaultSecretsDir is the only directory ${file:} placeholders may read
+// from unless SECRETS_DIR names another one.
+const defaultSecretsDir = "/run/secrets"
+
+// Secrets holds the values substituted while resolving a monitor so they can
+// be scrubbed from anything that gets stored or returned.
+type Secrets struct {
+	values []string
+}
+
+// ResolveSecrets returns a copy of the monitor with every secret placeholder
+// replaced by its value. The receiver is left untouched.
+func (a APIConfig) ResolveSecrets() (APIConfig, *Secrets, error) {
+	secrets := &Secrets{}
+	resolved := a
+
+	var err error
+	if resolved.URL, err = secrets.resolve(a.URL); err != nil {
+		return a, secrets, err
+	}
+	if resolved.Body, err = secrets.resolve(a.Body); err != nil {
+		return a, secrets, err
+	}
+	if resolved.Headers, err = secrets.resolveMap(a.Headers); err != nil {
+		return a, secrets, err
+	}
+	if resolved.Query, err = secrets.resolveMap(a.Query); err != nil {
+		return a, secrets, err
+	}
+	if a.TCP != nil {
+		tcp := *a.TCP
+		if tcp.Send, err = secrets.resolve(a.TCP.Send); err != nil {
+			return a, secrets, err
+		}
+		resolved.TCP = &tcp
+	}
+	if a.Ping != nil {
+		ping := *a.Ping
+		if ping.Payload, err = secrets.resolve(a.Ping.Payload); err != nil {
+			return a, secrets, err
+		}
+		resolved.Ping = &ping
+	}
+	if a.GRPC != nil {
+		grpcConfig := *a.GRPC
+		if grpcConfig.Metadata, err = secrets.resolveMap(a.GRPC.Metadata); err != nil {
+			return a, secrets, err
+		}
+		resolved.GRPC = &grpcConfig
+	}
+	if a.WebSocket != nil {
+		ws := *a.WebSocket
+		if ws.Send, err = secrets.resolve(a.WebSocket.Send); err != nil {
+			return a, secrets, err
+		}
+		resolved.WebSocket = &ws
+	}
+	if a.Steps != nil {
+		resolved.Steps = make([]StepConfig, len(a.Steps))
+		for i, step := range a.Steps {
+			if step.URL, err = secrets.resolve(step.URL); err != nil {
+				return a, secrets, err
+			}
+			if step.Body, err = secrets.resolve(step.Body); err != nil {
+				return a, secrets, err
+			}
+			if step.Headers, err = secrets.resolveMap(step.Headers); err != nil {
+				return a, secrets, err
+			}
+			if step.Query, err = secrets.resolveMap(step.Query); err != nil {
+				return a, secrets, err
+			}
+			resolved.Steps[i] = step
+		}
+	}
+
+	return resolved, secrets, nil
+}
+
+// Redact replaces every resolved secret value in s, including its
+// URL-escaped forms, with a fixed marker. Longer forms are replaced first so
+// a secret that contains another one is not left partly visible.
+func (s *Secrets) Redact(text string) string {
+	if s == nil {
+		return text
+	}
+
+	var forms []string
+	for _, value := range s.values {
+		forms = append(forms,
+			value,
+			url.QueryEscape(value),
+			url.PathEscape(value),
+			// How net/url prints the value as part of a path.
+			(&url.URL{Path: value}).EscapedPath(),
+		)
+	}
+	sort.Slice(forms, func(i, j int) bool { return len(forms[i]) > len(forms[j]) })
+
+	for _, form := range forms {
+		text = strings.ReplaceAll(text, form, redacted)
+	}
+	return text
+}
+
+func (s *Secrets) resolve(text string) (string, error) {
+	var resolveErr error
+	result := secretRef.ReplaceAllStringFunc(text, func(ref string) string {
+		match := secretRef.FindStringSubmatch(ref)
+		value, err := lookupSecret(match[1], match[2])
+		if err != nil {
+			if resolveErr == nil {
+				resolveErr = err
+			}
+			return ref
+		}
+		if value != "" {
+			s.values = append(s.values, value)
+		}
+		return value
+	})
+
+	return result, resolveErr
+}
+
+func (s *Secrets) resolveMap(values map[string]string) (map[string]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	resolved := make(map[string]string, len(values))
+	for key, value := range values {
+		v, err := s.resolve(value)
+		if err != nil {
+			return nil, err
+		}
+		resolved[key] = v
+	}
+
+	return resolved, nil
+}
+
+func lookupSecret(source, name string) (string, error) {
+	if err := checkSecretRef(source, name); err != nil {
+		return "", err
+	}
+
+	switch source {
+	case "env":
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("secret ${env:%s}: environment variable not set", name)
+		}
+		return value, nil
+	case "file":
+		path, err := secretFilePath(name)
+		if err != nil {
+			return "", err
+		}
+		// Resolve symlinks so a link inside the directory cannot point
+		// outside it.
+		real, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return "", fmt.Errorf("secret ${file:%s}: %v", name, err)
+		}
+		if _, err := secretFilePath(real); err != nil {
+			return "", err
+		}
+		data, err := os.ReadFile(real)
+		if err != nil {
+			return "", fmt.Errorf("secret ${file:%s}: %v", name, err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	return "", fmt.Errorf("unknown secret source %q", source)
+}
+
+// checkSecretRef reports placeholders that reach outside the allowed
+// environment variables and secrets directory.
+func checkSecretRef(source, name string) error {
+	switch source {
+	case "env":
+		if !strings.HasPrefix(name, SecretEnvPrefix) || name == SecretEnvPrefix {
+			return fmt.Errorf("secret ${env:%s}: only variables starting with %s may be used", name, SecretEnvPrefix)
+		}
+		return nil
+	case "file":
+		_, err := secretFilePath(name)
+		return err
+	}
+
+	return fmt.Errorf("unknown secret source %q", source)
+}
+
+// secretFilePath returns the path of a ${file:} placeholder, which is either
+// relative to the secrets directory or an absolute path inside it.
+func secretFilePath(name string) (string, error) {
+	dir, err := filepath.Abs(getEnv("SECRETS_DIR", defaultSecretsDir))
+	if err != nil {
+		return "", err
+	}
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = real
+	}
+
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	rel, err := filepath.Rel(dir, filepath.Clean(path))
+	if err != nil || rel == "." || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("secret ${file:%s}: path is outside %s", name, dir)
+	}
+
+	return filepath.Join(dir, rel), nil
+}
+
+// validateSecretRefs rejects placeholders that lookupSecret would refuse, so
+// they fail when the monitor is saved rather than on every check.
+func (a APIConfig) validateSecretRefs() error {
+	for _, text := range a.secretTexts() {
+		for _, match := range secretRef.FindAllStringSubmatch(text, -1) {
+			if err := checkSecretRef(match[1], match[2]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// secretTexts lists the fields ResolveSecrets resolves.
+func (a APIConfig) secretTexts() []string {
+	texts := []string{a.URL, a.Body}
+	texts = appendMapValues(texts, a.Headers)
+	texts = appendMapValues(texts, a.Query)
+	if a.TCP != nil {
+		texts = append(texts, a.TCP.Send)
+	}
+	if a.Ping != nil {
+		texts = append(texts, a.Ping.Payload)
+	}
+	if a.GRPC != nil {
+		texts = appendMapValues(texts, a.GRPC.Metadata)
+	}
+	if a.WebSocket != nil {
+		texts = append(texts, a.WebSocket.Send)
+	}
+	for _, step := range a.Steps {
+		texts = append(texts, step.URL, step.Body)
+		texts = appendMapValues(texts, step.Headers)
+		texts = appendMapValues(texts, step.Query)
+	}
+	return texts
+}
+
+func appendMapValues(texts []string, values map[string]string) []string {
+	for _, value := range values {
+		texts = append(texts, value)
+	}
+	return texts
+}
+
+// withoutSecretRefs substitutes a harmless token for each placeholder so
+// values such as URLs can be validated before their secrets are known.
+func withoutSecretRefs(text string) string {
+	return secretRef.ReplaceAllString(text, "secret")
+}
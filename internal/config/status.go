@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StatusRange is an inclusive range of HTTP status codes. A single code has
+// Min == Max.
+type StatusRange struct {
+	Min int
+	Max int
+}
+
+func (r StatusRange) String() string {
+	if r.Min == r.Max {
+		return strconv.Itoa(r.Min)
+	}
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
+
+// Contains reports whether code is within the range.
+func (r StatusRange) Contains(code int) bool {
+	return code >= r.Min && code <= r.Max
+}
+
+// ParseStatusRanges parses a comma-separated list of status codes and
+// ranges such as "200-299,401".
+func ParseStatusRanges(s string) ([]StatusRange, error) {
+	var ranges []StatusRange
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("invalid expected_statuses %q: empty entry", s)
+		}
+
+		low, high, isRange := strings.Cut(part, "-")
+		min, err := parseStatusCode(low)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expected_statuses %q: %v", s, err)
+		}
+		max := min
+		if isRange {
+			if max, err = parseStatusCode(high); err != nil {
+				return nil, fmt.Errorf("invalid expected_statuses %q: %v", s, err)
+			}
+			if max < min {
+				return nil, fmt.Errorf("invalid expected_statuses %q: range %s is reversed", s, part)
+			}
+		}
+
+		ranges = append(ranges, StatusRange{Min: min, Max: max})
+	}
+
+	return ranges, nil
+}
+
+func parseStatusCode(s string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || code < 100 || code > 599 {
+		return 0, fmt.Errorf("status code %q must be between 100 and 599", s)
+	}
+	return code, nil
+}
+
+// ExpectedStatusRanges returns the status codes a monitor accepts:
+// ExpectedStatuses when set, otherwise ExpectedStatus alone.
+func (a APIConfig) ExpectedStatusRanges() ([]StatusRange, error) {
+	if a.ExpectedStatuses == "" {
+		return []StatusRange{{Min: a.ExpectedStatus, Max: a.ExpectedStatus}}, nil
+	}
+	return ParseStatusRanges(a.ExpectedStatuses)
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStatusRanges(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []StatusRange
+		wantErr bool
+	}{
+		{input: "200", want: []StatusRange{{200, 200}}},
+		{input: "200-299", want: []StatusRange{{200, 299}}},
+		{input: "200-299,401", want: []StatusRange{{200, 299}, {401, 401}}},
+		{input: " 200 - 204 , 301 ", want: []StatusRange{{200, 204}, {301, 301}}},
+		{input: "204-204", want: []StatusRange{{204, 204}}},
+		{input: "100-599", want: []StatusRange{{100, 599}}},
+		{input: "401,200-299", want: []StatusRange{{401, 401}, {200, 299}}},
+		{input: "", wantErr: true},
+		{input: "200,", wantErr: true},
+		{input: ",200", wantErr: true},
+		{input: "200-", wantErr: true},
+		{input: "-200", wantErr: true},
+		{input: "299-200", wantErr: true},
+		{input: "200-299-300", wantErr: true},
+		{input: "2xx", wantErr: true},
+		{input: "99", wantErr: true},
+		{input: "600", wantErr: true},
+		{input: "200-600", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseStatusRanges(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseStatusRanges(%q) = %v, want an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStatusRanges(%q): %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseStatusRanges(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusRangeString(t *testing.T) {
+	tests := []struct {
+		r    StatusRange
+		want string
+	}{
+		{StatusRange{200, 200}, "200"},
+		{StatusRange{200, 299}, "200-299"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.r.String(); got != tt.want {
+			t.Errorf("%#v.String() = %q, want %q", tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestExpectedStatusRanges(t *testing.T) {
+	tests := []struct {
+		name      string
+		apiConfig APIConfig
+		want      []StatusRange
+	}{
+		{"expected_status", APIConfig{ExpectedStatus: 204}, []StatusRange{{204, 204}}},
+		{"expected_statuses overrides", APIConfig{ExpectedStatus: 204, ExpectedStatuses: "200-299"}, []StatusRange{{200, 299}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.apiConfig.ExpectedStatusRanges()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpectedStatusRanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
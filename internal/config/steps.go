@@ -0,0 +1,219 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"railway-api-uptime-monitor/internal/jsonpath"
+)
+
+// Extraction types supported on step responses.
+const (
+	ExtractJSONPath = "jsonpath"
+	ExtractHeader   = "header"
+	ExtractRegex    = "regex"
+)
+
+// variableRef matches ${var:NAME} placeholders, which refer to values
+// extracted by earlier steps.
+var variableRef = regexp.MustCompile(`\$\{var:([^}]*)\}`)
+
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// StepConfig is one request of a "steps" monitor. Its fields mean the same as
+// on an HTTP monitor and may use ${var:NAME} placeholders.
+type StepConfig struct {
+	Name             string            `json:"name" bson:"name"`
+	URL              string            `json:"url" bson:"url"`
+	Method           string            `json:"method" bson:"method"`
+	Headers          map[string]string `json:"headers,omitempty" bson:"headers,omitempty"`
+	Query            map[string]string `json:"query,omitempty" bson:"query,omitempty"`
+	Body             string            `json:"body,omitempty" bson:"body,omitempty"`
+	ContentType      string            `json:"content_type,omitempty" bson:"content_type,omitempty"`
+	ExpectedStatus   int               `json:"expected_status" bson:"expected_status"`
+	ExpectedStatuses string            `json:"expected_statuses,omitempty" bson:"expected_statuses,omitempty"`
+	Assertions       []Assertion       `json:"assertions,omitempty" bson:"assertions,omitempty"`
+	Extract          []Extraction      `json:"extract,omitempty" bson:"extract,omitempty"`
+}
+
+// Extraction stores a value of a step's response in a variable. Path is the
+// JSONPath for jsonpath, the header name for header, and the regular
+// expression for regex, whose first capture group (or whole match) is kept.
+type Extraction struct {
+	Name string `json:"name" bson:"name"`
+	Type string `json:"type" bson:"type"`
+	Path string `json:"path" bson:"path"`
+}
+
+func (e Extraction) Validate() error {
+	if !variableName.MatchString(e.Name) {
+		return fmt.Errorf("invalid variable name %q", e.Name)
+	}
+
+	switch e.Type {
+	case ExtractJSONPath:
+		if err := jsonpath.Compile(e.Path); err != nil || e.Path == "" {
+			return fmt.Errorf("invalid path %q", e.Path)
+		}
+	case ExtractHeader:
+		if e.Path == "" {
+			return errors.New("header name is required")
+		}
+	case ExtractRegex:
+		if _, err := regexp.Compile(e.Path); err != nil {
+			return fmt.Errorf("invalid regex: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown extraction type %q", e.Type)
+	}
+
+	return nil
+}
+
+// StepRequest returns the HTTP monitor that runs step i of a "steps" monitor,
+// with its ${var:NAME} placeholders replaced from vars.
+func (a APIConfig) StepRequest(i int, vars map[string]string) (APIConfig, error) {
+	step := a.Steps[i]
+	request := APIConfig{
+		Name:             a.Name,
+		Type:             TypeHTTP,
+		Method:           step.Method,
+		ContentType:      step.ContentType,
+		ExpectedStatus:   step.ExpectedStatus,
+		ExpectedStatuses: step.ExpectedStatuses,
+		Timeout:          a.Timeout,
+		CertExpiryDays:   a.CertExpiryDays,
+		Assertions:       step.Assertions,
+	}
+
+	var err error
+	if request.URL, err = expandVariables(step.URL, vars); err != nil {
+		return request, err
+	}
+	if request.Body, err = expandVariables(step.Body, vars); err != nil {
+		return request, err
+	}
+	if request.Headers, err = expandVariableMap(step.Headers, vars); err != nil {
+		return request, err
+	}
+	if request.Query, err = expandVariableMap(step.Query, vars); err != nil {
+		return request, err
+	}
+
+	return request, nil
+}
+
+func (a *APIConfig) validateSteps() error {
+	if len(a.Steps) == 0 {
+		return errors.New("at least one step is required")
+	}
+
+	defined := map[string]string{}
+	for i, step := range a.Steps {
+		label := fmt.Sprintf("step %d", i+1)
+		if step.Name != "" {
+			label = fmt.Sprintf("step %d (%s)", i+1, step.Name)
+		}
+
+		for _, ref := range stepVariableRefs(step) {
+			if _, ok := defined[ref]; !ok {
+				return fmt.Errorf("%s: variable %q is not extracted by an earlier step", label, ref)
+			}
+		}
+
+		// Validate the request with every variable set to a placeholder.
+		request, err := a.StepRequest(i, defined)
+		if err != nil {
+			return fmt.Errorf("%s: %v", label, err)
+		}
+		// A URL that starts with a variable, such as a Location header or
+		// a next link, gets its scheme and host when the step runs.
+		if loc := variableRef.FindStringIndex(step.URL); loc != nil && loc[0] == 0 {
+			rest, _ := expandVariables(step.URL[loc[1]:], defined)
+			request.URL = "http://var.invalid" + rest
+		}
+		if err := request.validateHTTP(); err != nil {
+			return fmt.Errorf("%s: %v", label, err)
+		}
+
+		for j, extraction := range step.Extract {
+			if err := extraction.Validate(); err != nil {
+				return fmt.Errorf("%s: extraction %d: %v", label, j+1, err)
+			}
+			defined[extraction.Name] = "var"
+		}
+	}
+
+	return nil
+}
+
+// stepVariableRefs lists the variables a step refers to.
+func stepVariableRefs(step StepConfig) []string {
+	texts := []string{step.URL, step.Body}
+	for _, value := range step.Headers {
+		texts = append(texts, value)
+	}
+	for _, value := range step.Query {
+		texts = append(texts, value)
+	}
+
+	var refs []string
+	for _, text := range texts {
+		for _, match := range variableRef.FindAllStringSubmatch(text, -1) {
+			refs = append(refs, match[1])
+		}
+	}
+	return refs
+}
+
+func expandVariables(text string, vars map[string]string) (string, error) {
+	var expandErr error
+	result := variableRef.ReplaceAllStringFunc(text, func(ref string) string {
+		name := variableRef.FindStringSubmatch(ref)[1]
+		value, ok := vars[name]
+		if !ok {
+			if expandErr == nil {
+				expandErr = fmt.Errorf("variable %q is not set", name)
+			}
+			return ref
+		}
+		return value
+	})
+
+	return result, expandErr
+}
+
+func expandVariableMap(values map[string]string, vars map[string]string) (map[string]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	expanded := make(map[string]string, len(values))
+	for key, value := range values {
+		v, err := expandVariables(value, vars)
+		if err != nil {
+			return nil, err
+		}
+		expanded[key] = v
+	}
+
+	return expanded, nil
+}
+
+// applyStepDefaults normalizes the method and expected status of each step.
+func (a *APIConfig) applyStepDefaults() {
+	for i := range a.Steps {
+		step := &a.Steps[i]
+		step.Name = strings.TrimSpace(step.Name)
+		step.URL = strings.TrimSpace(step.URL)
+		step.Method = strings.ToUpper(strings.TrimSpace(step.Method))
+		if step.Method == "" {
+			step.Method = defaultMethod
+		}
+		if step.ExpectedStatus == 0 {
+			step.ExpectedStatus = defaultExpectedStatus
+		}
+	}
+}
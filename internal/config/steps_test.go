@@ -0,0 +1,56 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSteps(t *testing.T) {
+	create := StepConfig{
+		Name:           "create",
+		URL:            "https://api.example.com/orders",
+		Method:         "POST",
+		ExpectedStatus: 201,
+		Extract: []Extraction{
+			{Name: "location", Type: ExtractHeader, Path: "Location"},
+			{Name: "id", Type: ExtractJSONPath, Path: "$.id"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{name: "literal", url: "https://api.example.com/orders/${var:id}"},
+		{name: "variable host", url: "https://${var:id}.example.com/orders"},
+		{name: "variable url", url: "${var:location}"},
+		{name: "variable url with path", url: "${var:location}/items?id=${var:id}"},
+		{name: "undefined variable", url: "${var:next}", wantErr: `variable "next" is not extracted by an earlier step`},
+		{name: "bad literal scheme", url: "ftp://api.example.com/${var:id}", wantErr: "url scheme must be http or https"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiConfig := APIConfig{
+				Name: "checkout",
+				Type: TypeSteps,
+				Steps: []StepConfig{
+					create,
+					{Name: "fetch", URL: tt.url, Method: "GET", ExpectedStatus: 200},
+				},
+			}
+
+			err := apiConfig.validateSteps()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateSteps: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateSteps = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,424 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Monitor types.
+const (
+	TypeHTTP      = "http"
+	TypeTCP       = "tcp"
+	TypeDNS       = "dns"
+	TypePing      = "ping"
+	TypeGRPC      = "grpc"
+	TypeWebSocket = "websocket"
+	TypePush      = "push"
+	TypeSteps     = "steps"
+)
+
+const (
+	defaultPingCount        = 5
+	defaultPingMaxLoss      = 20.0
+	defaultPingProbeTimeout = "1s"
+	maxPingCount            = 100
+
+	defaultPushPeriod = "1h"
+	defaultPushGrace  = "5m"
+)
+
+// pushToken restricts heartbeat tokens to URL-safe characters.
+var pushToken = regexp.MustCompile(`^[A-Za-z0-9_-]{16,64}$`)
+
+var dnsRecordTypes = map[string]bool{
+	"A": true, "AAAA": true, "CNAME": true, "MX": true, "TXT": true,
+}
+
+const (
+	defaultMethod         = "GET"
+	defaultExpectedStatus = 200
+	maxTimeout            = 300
+	maxRetries            = 10
+)
+
+var allowedMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"POST":    true,
+	"PUT":     true,
+	"PATCH":   true,
+	"DELETE":  true,
+	"OPTIONS": true,
+}
+
+// ApplyDefaults fills in optional fields left empty by the caller.
+func (a *APIConfig) ApplyDefaults() {
+	a.Name = strings.TrimSpace(a.Name)
+	a.URL = strings.TrimSpace(a.URL)
+	a.Type = strings.ToLower(strings.TrimSpace(a.Type))
+	a.Method = strings.ToUpper(strings.TrimSpace(a.Method))
+
+	if a.Type == "" {
+		a.Type = TypeHTTP
+	}
+	if a.Type == TypeDNS {
+		if a.DNS == nil {
+			a.DNS = &DNSConfig{}
+		}
+		a.DNS.RecordType = strings.ToUpper(strings.TrimSpace(a.DNS.RecordType))
+		if a.DNS.RecordType == "" {
+			a.DNS.RecordType = "A"
+		}
+	}
+	if a.Type == TypePing {
+		if a.Ping == nil {
+			a.Ping = &PingConfig{}
+		}
+		a.Ping.Protocol = strings.ToLower(strings.TrimSpace(a.Ping.Protocol))
+		if a.Ping.Protocol == "" {
+			a.Ping.Protocol = "tcp"
+		}
+		if a.Ping.Count == 0 {
+			a.Ping.Count = defaultPingCount
+		}
+		if a.Ping.MaxLossPercent == nil {
+			maxLoss := defaultPingMaxLoss
+			a.Ping.MaxLossPercent = &maxLoss
+		}
+		if a.Ping.ProbeTimeout == "" {
+			a.Ping.ProbeTimeout = defaultPingProbeTimeout
+		}
+	}
+	if a.Type == TypePush {
+		if a.Push == nil {
+			a.Push = &PushConfig{}
+		}
+		a.Push.Token = strings.TrimSpace(a.Push.Token)
+		if a.Push.Token == "" {
+			a.Push.Token = NewPushToken()
+		}
+		if a.Push.Period == "" {
+			a.Push.Period = defaultPushPeriod
+		}
+		if a.Push.Grace == "" {
+			a.Push.Grace = defaultPushGrace
+		}
+	}
+	if a.Type == TypeSteps {
+		a.applyStepDefaults()
+	}
+	if a.Type != TypeHTTP {
+		return
+	}
+
+	if a.Method == "" {
+		a.Method = defaultMethod
+	}
+	if a.ExpectedStatus == 0 {
+		a.ExpectedStatus = defaultExpectedStatus
+	}
+}
+
+// Validate reports the first problem found in a monitor definition.
+func (a *APIConfig) Validate() error {
+	if a.Name == "" {
+		return errors.New("name is required")
+	}
+	if strings.Contains(a.Name, "/") {
+		return errors.New("name must not contain '/'")
+	}
+
+	// Push monitors receive heartbeats instead of checking a URL, and steps
+	// monitors have a URL per step.
+	if a.URL == "" && a.Type != TypePush && a.Type != TypeSteps {
+		return errors.New("url is required")
+	}
+
+	if err := a.validateSecretRefs(); err != nil {
+		return err
+	}
+
+	switch a.Type {
+	case TypeHTTP:
+		if err := a.validateHTTP(); err != nil {
+			return err
+		}
+	case TypeTCP:
+		if err := a.validateTCP(); err != nil {
+			return err
+		}
+	case TypeDNS:
+		if err := a.validateDNS(); err != nil {
+			return err
+		}
+	case TypePing:
+		if err := a.validatePing(); err != nil {
+			return err
+		}
+	case TypeGRPC:
+		if _, err := HostPort(withoutSecretRefs(a.URL)); err != nil {
+			return err
+		}
+	case TypeWebSocket:
+		if err := a.validateWebSocket(); err != nil {
+			return err
+		}
+	case TypePush:
+		if err := a.validatePush(); err != nil {
+			return err
+		}
+	case TypeSteps:
+		if err := a.validateSteps(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown monitor type %q", a.Type)
+	}
+
+	if a.Content != nil && a.Type != TypeHTTP {
+		return errors.New("content tracking is only supported on http monitors")
+	}
+
+	if a.SLO != nil {
+		if a.Type == TypePush {
+			return errors.New("slo is not supported on push monitors")
+		}
+		warning, critical, err := a.SLO.Thresholds()
+		if err != nil {
+			return err
+		}
+		if warning == 0 && critical == 0 {
+			return errors.New("slo needs a warning or critical threshold")
+		}
+		if warning > 0 && critical > 0 && critical < warning {
+			return errors.New("slo.critical must not be below slo.warning")
+		}
+	}
+
+	if a.Timeout < 0 || a.Timeout > maxTimeout {
+		return fmt.Errorf("timeout must be between 0 and %d seconds, got %d", maxTimeout, a.Timeout)
+	}
+
+	if a.Interval != "" {
+		if _, err := ParseInterval(a.Interval); err != nil {
+			return err
+		}
+	}
+
+	if a.Retries != nil && (*a.Retries < 0 || *a.Retries > maxRetries) {
+		return fmt.Errorf("retries must be between 0 and %d, got %d", maxRetries, *a.Retries)
+	}
+	if a.RetryBackoff != "" {
+		if d, err := time.ParseDuration(a.RetryBackoff); err != nil || d < 0 {
+			return fmt.Errorf("invalid retry_backoff %q", a.RetryBackoff)
+		}
+	}
+
+	if a.ReminderInterval != "" {
+		if d, err := time.ParseDuration(a.ReminderInterval); err != nil || d < 0 {
+			return fmt.Errorf("invalid reminder_interval %q", a.ReminderInterval)
+		}
+	}
+
+	for _, days := range a.CertExpiryDays {
+		if days <= 0 {
+			return fmt.Errorf("cert_expiry_days must be positive, got %d", days)
+		}
+	}
+
+	for i, rule := range a.Notify {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("notify rule %d: %v", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+func (a *APIConfig) validateHTTP() error {
+	u, err := url.Parse(withoutSecretRefs(a.URL))
+	if err != nil {
+		return fmt.Errorf("invalid url: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("url scheme must be http or https, got %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return errors.New("url must include a host")
+	}
+
+	if !allowedMethods[a.Method] {
+		return fmt.Errorf("unsupported method %q", a.Method)
+	}
+
+	if a.Body != "" && a.Method == "HEAD" {
+		return errors.New("body is not allowed with HEAD requests")
+	}
+	for name := range a.Headers {
+		if strings.TrimSpace(name) == "" || strings.ContainsAny(name, " :\r\n") {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+	}
+	for name := range a.Query {
+		if name == "" {
+			return errors.New("query parameter names must not be empty")
+		}
+	}
+
+	if a.ExpectedStatuses != "" {
+		if _, err := ParseStatusRanges(a.ExpectedStatuses); err != nil {
+			return err
+		}
+	} else if a.ExpectedStatus < 100 || a.ExpectedStatus > 599 {
+		return fmt.Errorf("expected_status must be between 100 and 599, got %d", a.ExpectedStatus)
+	}
+
+	for i, assertion := range a.Assertions {
+		if err := assertion.Validate(); err != nil {
+			return fmt.Errorf("assertion %d: %v", i+1, err)
+		}
+	}
+
+	if a.Content != nil {
+		if err := a.Content.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *APIConfig) validateTCP() error {
+	if _, err := HostPort(withoutSecretRefs(a.URL)); err != nil {
+		return err
+	}
+
+	if a.TCP != nil && a.TCP.Expect != "" {
+		if _, err := regexp.Compile(a.TCP.Expect); err != nil {
+			return fmt.Errorf("invalid tcp.expect: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (a *APIConfig) validateDNS() error {
+	if strings.ContainsAny(a.URL, " /:") {
+		return fmt.Errorf("url must be the name to resolve, got %q", a.URL)
+	}
+	if a.DNS == nil || !dnsRecordTypes[a.DNS.RecordType] {
+		return errors.New("dns.record_type must be one of A, AAAA, CNAME, MX, TXT")
+	}
+	if a.DNS.Resolver != "" {
+		if _, err := ResolverAddress(a.DNS.Resolver); err != nil {
+			return fmt.Errorf("invalid dns.resolver: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (a *APIConfig) validateWebSocket() error {
+	u, err := url.Parse(withoutSecretRefs(a.URL))
+	if err != nil {
+		return fmt.Errorf("invalid url: %v", err)
+	}
+	if u.Scheme != "ws" && u.Scheme != "wss" {
+		return fmt.Errorf("url scheme must be ws or wss, got %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return errors.New("url must include a host")
+	}
+
+	for name := range a.Headers {
+		if strings.TrimSpace(name) == "" || strings.ContainsAny(name, " :\r\n") {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+	}
+
+	if a.WebSocket != nil && a.WebSocket.Expect != "" {
+		if _, err := regexp.Compile(a.WebSocket.Expect); err != nil {
+			return fmt.Errorf("invalid websocket.expect: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (a *APIConfig) validatePush() error {
+	if a.Push == nil {
+		return errors.New("push options are required")
+	}
+	if !pushToken.MatchString(a.Push.Token) {
+		return errors.New("push.token must be 16 to 64 letters, digits, '-' or '_'")
+	}
+	if d, err := time.ParseDuration(a.Push.Period); err != nil || d < MinInterval {
+		return fmt.Errorf("push.period must be a duration of at least %s, got %q", MinInterval, a.Push.Period)
+	}
+	if d, err := time.ParseDuration(a.Push.Grace); err != nil || d < 0 {
+		return fmt.Errorf("invalid push.grace %q", a.Push.Grace)
+	}
+
+	return nil
+}
+
+func (a *APIConfig) validatePing() error {
+	if _, err := HostPort(withoutSecretRefs(a.URL)); err != nil {
+		return err
+	}
+	if a.Ping == nil {
+		return errors.New("ping options are required")
+	}
+	if a.Ping.Protocol != "tcp" && a.Ping.Protocol != "udp" {
+		return fmt.Errorf("ping.protocol must be tcp or udp, got %q", a.Ping.Protocol)
+	}
+	if a.Ping.Count < 1 || a.Ping.Count > maxPingCount {
+		return fmt.Errorf("ping.count must be between 1 and %d, got %d", maxPingCount, a.Ping.Count)
+	}
+	if a.Ping.MaxLossPercent != nil && (*a.Ping.MaxLossPercent < 0 || *a.Ping.MaxLossPercent > 100) {
+		return fmt.Errorf("ping.max_loss_percent must be between 0 and 100, got %v", *a.Ping.MaxLossPercent)
+	}
+	if d, err := time.ParseDuration(a.Ping.ProbeTimeout); err != nil || d <= 0 {
+		return fmt.Errorf("invalid ping.probe_timeout %q", a.Ping.ProbeTimeout)
+	}
+
+	return nil
+}
+
+// ResolverAddress returns a DNS resolver address as "host:port", defaulting
+// the port to 53.
+func ResolverAddress(resolver string) (string, error) {
+	if _, _, err := net.SplitHostPort(resolver); err != nil {
+		resolver = net.JoinHostPort(strings.Trim(resolver, "[]"), "53")
+	}
+	return HostPort(resolver)
+}
+
+// HostPort extracts "host:port" from a target written either as
+// "host:port" or as "scheme://host:port".
+func HostPort(target string) (string, error) {
+	address := target
+	if i := strings.Index(address, "://"); i >= 0 {
+		address = address[i+3:]
+	}
+	address = strings.TrimSuffix(address, "/")
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid address %q: %v", target, err)
+	}
+	if host == "" {
+		return "", fmt.Errorf("invalid address %q: missing host", target)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("invalid address %q: bad port %q", target, port)
+	}
+
+	return address, nil
+}
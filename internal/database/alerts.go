@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"railway-api-uptime-monitor/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ResolveLegacyAlerts resolves the unresolved alerts that were never
+// notified as open alerts: before outages were tracked in place, every failed
+// check stored its own "down" or "timeout" alert and none was ever resolved,
+// and one-off event alerts were stored unresolved too. Left alone they would
+// be taken for the open alert of the next outage and counted as unresolved
+// forever. It returns how many alerts were resolved.
+func (db *Database) ResolveLegacyAlerts(ctx context.Context) (int64, error) {
+	result, err := db.GetCollection("alerts").UpdateMany(ctx,
+		bson.M{
+			"resolved":         false,
+			"last_notified_at": bson.M{"$exists": false},
+		},
+		bson.M{"$set": bson.M{"resolved": true}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// ResolveOpenAlerts resolves every open alert of the monitor, recording when
+// it resolved and how long it lasted. It returns how many alerts were
+// resolved.
+func (db *Database) ResolveOpenAlerts(ctx context.Context, apiName string) (int, error) {
+	collection := db.GetCollection("alerts")
+
+	cursor, err := collection.Find(ctx, bson.M{"api_name": apiName, "resolved": false})
+	if err != nil {
+		return 0, err
+	}
+	var alerts []models.Alert
+	if err := cursor.All(ctx, &alerts); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for i, alert := range alerts {
+		_, err := collection.UpdateByID(ctx, alert.ID, bson.M{"$set": bson.M{
+			"resolved":    true,
+			"resolved_at": now,
+			"duration":    now.Sub(alert.Timestamp),
+			"updated_at":  now,
+		}})
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(alerts), nil
+}
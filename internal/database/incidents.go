@@ -0,0 +1,225 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"railway-api-uptime-monitor/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MaxIncidentChecks caps the failed checks kept on an incident's timeline.
+// Later failures only count towards failed_checks, so long outages do not
+// grow the document without bound.
+const MaxIncidentChecks = 20
+
+var (
+	ErrIncidentNotFound = errors.New("incident not found")
+	ErrIncidentResolved = errors.New("incident already resolved")
+)
+
+func (db *Database) incidents() *mongo.Collection {
+	return db.GetCollection("incidents")
+}
+
+// ListIncidents returns the most recent incidents, newest first. status
+// filters by incident status; "active" matches open and acknowledged ones and
+// an empty status matches all.
+func (db *Database) ListIncidents(ctx context.Context, status string, limit int64) ([]models.Incident, error) {
+	filter := bson.M{}
+	switch status {
+	case "":
+	case "active":
+		filter["status"] = bson.M{"$ne": models.IncidentResolved}
+	default:
+		filter["status"] = status
+	}
+
+	opts := options.Find().
+		SetLimit(limit).
+		SetSort(bson.M{"started_at": -1})
+
+	cursor, err := db.incidents().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	incidents := []models.Incident{}
+	if err := cursor.All(ctx, &incidents); err != nil {
+		return nil, err
+	}
+
+	return incidents, nil
+}
+
+func (db *Database) GetIncident(ctx context.Context, id primitive.ObjectID) (*models.Incident, error) {
+	var incident models.Incident
+	err := db.incidents().FindOne(ctx, bson.M{"_id": id}).Decode(&incident)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrIncidentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &incident, nil
+}
+
+// ActiveIncident returns the monitor's unresolved incident, or nil if it has
+// none.
+func (db *Database) ActiveIncident(ctx context.Context, apiName string) (*models.Incident, error) {
+	var incident models.Incident
+	filter := bson.M{"api_name": apiName, "status": bson.M{"$ne": models.IncidentResolved}}
+	err := db.incidents().FindOne(ctx, filter).Decode(&incident)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &incident, nil
+}
+
+// RecordIncidentCheck adds a failed check to the monitor's active incident,
+// opening a new incident if it has none. An incident resolved by hand while
+// the check ran is left alone, and the check opens a new one.
+func (db *Database) RecordIncidentCheck(ctx context.Context, apiName string, event models.IncidentEvent) (*models.Incident, error) {
+	incident, err := db.ActiveIncident(ctx, apiName)
+	if err != nil {
+		return nil, err
+	}
+	if incident == nil {
+		return db.openIncident(ctx, apiName, event)
+	}
+
+	update := bson.M{
+		"$set": bson.M{"summary": event.Message, "updated_at": event.Timestamp},
+		"$inc": bson.M{"failed_checks": 1},
+	}
+	if incident.FailedChecks < MaxIncidentChecks {
+		update["$push"] = bson.M{"timeline": event}
+	}
+
+	filter := bson.M{"_id": incident.ID, "status": bson.M{"$ne": models.IncidentResolved}}
+	incident, err = db.updateIncident(ctx, filter, update)
+	if errors.Is(err, ErrIncidentNotFound) {
+		return db.openIncident(ctx, apiName, event)
+	}
+	return incident, err
+}
+
+// openIncident opens a new incident for the monitor, starting with event.
+func (db *Database) openIncident(ctx context.Context, apiName string, event models.IncidentEvent) (*models.Incident, error) {
+	incident := &models.Incident{
+		APIName:      apiName,
+		Status:       models.IncidentOpen,
+		Summary:      event.Message,
+		StartedAt:    event.Timestamp,
+		UpdatedAt:    event.Timestamp,
+		FailedChecks: 1,
+		Timeline:     []models.IncidentEvent{event},
+	}
+	result, err := db.incidents().InsertOne(ctx, incident)
+	if err != nil {
+		return nil, err
+	}
+	incident.ID = result.InsertedID.(primitive.ObjectID)
+	return incident, nil
+}
+
+// AddIncidentEvent appends an event to an incident's timeline.
+func (db *Database) AddIncidentEvent(ctx context.Context, id primitive.ObjectID, event models.IncidentEvent) (*models.Incident, error) {
+	return db.updateIncident(ctx, bson.M{"_id": id}, bson.M{
+		"$set":  bson.M{"updated_at": event.Timestamp},
+		"$push": bson.M{"timeline": event},
+	})
+}
+
+// AcknowledgeIncident marks an open incident as acknowledged, which stops its
+// reminder notifications. Acknowledging it again has no effect.
+func (db *Database) AcknowledgeIncident(ctx context.Context, id primitive.ObjectID, author, message string) (*models.Incident, error) {
+	now := time.Now()
+	incident, err := db.updateIncident(ctx, bson.M{"_id": id, "status": models.IncidentOpen}, bson.M{
+		"$set": bson.M{
+			"status":          models.IncidentAcknowledged,
+			"acknowledged_at": now,
+			"acknowledged_by": author,
+			"updated_at":      now,
+		},
+		"$push": bson.M{"timeline": models.IncidentEvent{
+			Type:      "acknowledged",
+			Timestamp: now,
+			Message:   message,
+			Author:    author,
+		}},
+	})
+	if !errors.Is(err, ErrIncidentNotFound) {
+		return incident, err
+	}
+
+	incident, err = db.GetIncident(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if incident.Status == models.IncidentResolved {
+		return nil, ErrIncidentResolved
+	}
+	return incident, nil
+}
+
+// ResolveIncident closes an incident, recording when it resolved and how long
+// it lasted.
+func (db *Database) ResolveIncident(ctx context.Context, id primitive.ObjectID, author, message string) (*models.Incident, error) {
+	incident, err := db.GetIncident(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if incident.Status == models.IncidentResolved {
+		return nil, ErrIncidentResolved
+	}
+
+	now := time.Now()
+	incident, err = db.updateIncident(ctx, bson.M{"_id": id, "status": bson.M{"$ne": models.IncidentResolved}}, bson.M{
+		"$set": bson.M{
+			"status":      models.IncidentResolved,
+			"resolved_at": now,
+			"resolved_by": author,
+			"duration":    now.Sub(incident.StartedAt),
+			"updated_at":  now,
+		},
+		"$push": bson.M{"timeline": models.IncidentEvent{
+			Type:      "resolved",
+			Timestamp: now,
+			Message:   message,
+			Author:    author,
+		}},
+	})
+	if errors.Is(err, ErrIncidentNotFound) {
+		return nil, ErrIncidentResolved
+	}
+	return incident, err
+}
+
+// updateIncident applies update to the incident matching filter and returns
+// the updated incident.
+func (db *Database) updateIncident(ctx context.Context, filter, update bson.M) (*models.Incident, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var incident models.Incident
+	err := db.incidents().FindOneAndUpdate(ctx, filter, update, opts).Decode(&incident)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrIncidentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &incident, nil
+}
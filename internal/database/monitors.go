@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrMonitorNotFound = errors.New("monitor not found")
+	ErrMonitorExists   = errors.New("monitor already exists")
+)
+
+func (db *Database) monitors() *mongo.Collection {
+	return db.GetCollection("monitors")
+}
+
+// EnsureMonitorIndexes makes monitor names unique so they can keep serving as
+// the key for the api_status and health_checks collections. Heartbeat tokens
+// of push monitors are unique too.
+func (db *Database) EnsureMonitorIndexes(ctx context.Context) error {
+	_, err := db.monitors().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "push.token", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+	})
+	return err
+}
+
+func (db *Database) ListMonitors(ctx context.Context) ([]config.APIConfig, error) {
+	cursor, err := db.monitors().Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"name": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	monitors := []config.APIConfig{}
+	if err := cursor.All(ctx, &monitors); err != nil {
+		return nil, err
+	}
+
+	return monitors, nil
+}
+
+func (db *Database) GetMonitor(ctx context.Context, name string) (*config.APIConfig, error) {
+	var monitor config.APIConfig
+	err := db.monitors().FindOne(ctx, bson.M{"name": name}).Decode(&monitor)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrMonitorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &monitor, nil
+}
+
+// GetMonitorByPushToken returns the push monitor owning a heartbeat token.
+func (db *Database) GetMonitorByPushToken(ctx context.Context, token string) (*config.APIConfig, error) {
+	var monitor config.APIConfig
+	err := db.monitors().FindOne(ctx, bson.M{"push.token": token}).Decode(&monitor)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrMonitorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &monitor, nil
+}
+
+func (db *Database) CreateMonitor(ctx context.Context, monitor *config.APIConfig) error {
+	now := time.Now()
+	monitor.CreatedAt = now
+	monitor.UpdatedAt = now
+
+	_, err := db.monitors().InsertOne(ctx, monitor)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrMonitorExists
+	}
+	return err
+}
+
+// ReplaceMonitor overwrites the monitor stored under name, keeping its
+// creation time.
+func (db *Database) ReplaceMonitor(ctx context.Context, name string, monitor *config.APIConfig) error {
+	existing, err := db.GetMonitor(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	monitor.CreatedAt = existing.CreatedAt
+	monitor.UpdatedAt = time.Now()
+
+	result, err := db.monitors().ReplaceOne(ctx, bson.M{"name": name}, monitor)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrMonitorNotFound
+	}
+
+	return nil
+}
+
+func (db *Database) DeleteMonitor(ctx context.Context, name string) error {
+	result, err := db.monitors().DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrMonitorNotFound
+	}
+
+	return nil
+}
+
+// SeedMonitors inserts the given monitors unless a monitor with the same name
+// is already stored, so edits made through the API survive a restart. It
+// returns the number of monitors imported.
+func (db *Database) SeedMonitors(ctx context.Context, monitors []config.APIConfig) (int, error) {
+	imported := 0
+	for i := range monitors {
+		err := db.CreateMonitor(ctx, &monitors[i])
+		if errors.Is(err, ErrMonitorExists) {
+			continue
+		}
+		if err != nil {
+			return imported, err
+		}
+		imported++
+	}
+
+	return imported, nil
+}
@@ -8,18 +8,21 @@ import (
 
 	"railway-api-uptime-monitor/internal/database"
 	"railway-api-uptime-monitor/internal/models"
+	"railway-api-uptime-monitor/internal/monitor"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type Handler struct {
-	db *database.Database
+	db      *database.Database
+	monitor *monitor.Monitor
 }
 
-func New(db *database.Database) *Handler {
-	return &Handler{db: db}
+func New(db *database.Database, m *monitor.Monitor) *Handler {
+	return &Handler{db: db, monitor: m}
 }
 
 func (h *Handler) HealthCheck(c *gin.Context) {
@@ -53,8 +56,17 @@ func (h *Handler) Dashboard(c *gin.Context) {
 		return
 	}
 
+	incidents, err := h.db.ListIncidents(ctx, "active", 20)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "dashboard.html", gin.H{
+			"error": "Failed to load incidents",
+		})
+		return
+	}
+
 	c.HTML(http.StatusOK, "dashboard.html", gin.H{
 		"apis":      apiStatuses,
+		"incidents": incidents,
 		"timestamp": time.Now().Format("2006-01-02 15:04:05"),
 	})
 }
@@ -187,8 +199,9 @@ func (h *Handler) GetStats(c *gin.Context) {
 	apiCollection := h.db.GetCollection("api_status")
 	totalAPIs, _ := apiCollection.CountDocuments(ctx, bson.M{})
 
-	// Get APIs that are up
+	// Get APIs that are up, and those up but slower than their SLO
 	upAPIs, _ := apiCollection.CountDocuments(ctx, bson.M{"status": "up"})
+	degradedAPIs, _ := apiCollection.CountDocuments(ctx, bson.M{"status": "degraded"})
 
 	// Get total checks in last 24 hours
 	checksCollection := h.db.GetCollection("health_checks")
@@ -204,15 +217,104 @@ func (h *Handler) GetStats(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"total_apis":        totalAPIs,
 		"apis_up":           upAPIs,
-		"apis_down":         totalAPIs - upAPIs,
+		"apis_degraded":     degradedAPIs,
+		"apis_down":         totalAPIs - upAPIs - degradedAPIs,
 		"total_checks_24h":  totalChecks,
 		"unresolved_alerts": unresolvedAlerts,
 		"uptime_percentage": func() float64 {
 			if totalAPIs == 0 {
 				return 100.0
 			}
-			return float64(upAPIs) / float64(totalAPIs) * 100.0
+			return float64(upAPIs+degradedAPIs) / float64(totalAPIs) * 100.0
 		}(),
 		"timestamp": time.Now(),
 	})
 }
+
+// GetAPIStats summarizes one monitor's checks over a window, including the
+// average duration of each HTTP phase.
+func (h *Handler) GetAPIStats(c *gin.Context) {
+	name := c.Param("name")
+	hoursStr := c.DefaultQuery("hours", "24")
+
+	hours, err := strconv.Atoi(hoursStr)
+	if err != nil || hours <= 0 {
+		hours = 24
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"api_name":  name,
+			"timestamp": bson.M{"$gte": since},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"total": bson.M{"$sum": 1},
+			"up": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$in": bson.A{"$status", bson.A{"up", "degraded"}}}, 1, 0},
+			}},
+			"degraded": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$eq": bson.A{"$status", "degraded"}}, 1, 0},
+			}},
+			"response_time":      bson.M{"$avg": "$response_time"},
+			"dns_lookup":         bson.M{"$avg": "$dns_lookup"},
+			"tcp_connect":        bson.M{"$avg": "$tcp_connect"},
+			"tls_handshake":      bson.M{"$avg": "$tls_handshake"},
+			"time_to_first_byte": bson.M{"$avg": "$time_to_first_byte"},
+			"content_transfer":   bson.M{"$avg": "$content_transfer"},
+		}}},
+	}
+
+	cursor, err := h.db.GetCollection("health_checks").Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Total           int64   `bson:"total"`
+		Up              int64   `bson:"up"`
+		Degraded        int64   `bson:"degraded"`
+		ResponseTime    float64 `bson:"response_time"`
+		DNSLookup       float64 `bson:"dns_lookup"`
+		TCPConnect      float64 `bson:"tcp_connect"`
+		TLSHandshake    float64 `bson:"tls_handshake"`
+		TimeToFirstByte float64 `bson:"time_to_first_byte"`
+		ContentTransfer float64 `bson:"content_transfer"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(results) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No checks found for API in window"})
+		return
+	}
+
+	stats := results[0]
+	toMs := func(nanos float64) float64 {
+		return nanos / float64(time.Millisecond)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_name":             name,
+		"window_hours":         hours,
+		"total_checks":         stats.Total,
+		"uptime_percentage":    float64(stats.Up) / float64(stats.Total) * 100.0,
+		"degraded_percentage":  float64(stats.Degraded) / float64(stats.Total) * 100.0,
+		"avg_response_time_ms": toMs(stats.ResponseTime),
+		"avg_phase_ms": gin.H{
+			"dns_lookup":         toMs(stats.DNSLookup),
+			"tcp_connect":        toMs(stats.TCPConnect),
+			"tls_handshake":      toMs(stats.TLSHandshake),
+			"time_to_first_byte": toMs(stats.TimeToFirstByte),
+			"content_transfer":   toMs(stats.ContentTransfer),
+		},
+		"timestamp": time.Now(),
+	})
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+	"railway-api-uptime-monitor/internal/monitor"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Heartbeat records a successful run of the job behind a push monitor. The
+// request body, if any, is stored as a log snippet.
+func (h *Handler) Heartbeat(c *gin.Context) {
+	h.recordHeartbeat(c, false)
+}
+
+// HeartbeatFail records a failed run of the job behind a push monitor.
+func (h *Handler) HeartbeatFail(c *gin.Context) {
+	h.recordHeartbeat(c, true)
+}
+
+func (h *Handler) recordHeartbeat(c *gin.Context, failed bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	apiConfig, err := h.db.GetMonitorByPushToken(ctx, c.Param("token"))
+	if err != nil {
+		respondMonitorError(c, err)
+		return
+	}
+	if apiConfig.Type != config.TypePush {
+		c.JSON(http.StatusNotFound, gin.H{"error": "monitor not found"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, monitor.MaxHeartbeatLogBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.monitor.RecordHeartbeat(*apiConfig, failed, string(body))
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "monitor": apiConfig.Name})
+}
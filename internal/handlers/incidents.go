@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"railway-api-uptime-monitor/internal/database"
+	"railway-api-uptime-monitor/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// incidentNote is the body of the acknowledge, comment and resolve routes.
+type incidentNote struct {
+	Author  string `json:"author"`
+	Message string `json:"message"`
+}
+
+// ListIncidents returns recent incidents. The status query parameter filters
+// them: "open", "acknowledged", "resolved", or "active" for the first two.
+func (h *Handler) ListIncidents(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", "active", models.IncidentOpen, models.IncidentAcknowledged, models.IncidentResolved:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status " + strconv.Quote(status)})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	incidents, err := h.db.ListIncidents(ctx, status, int64(limit))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"incidents": incidents,
+		"count":     len(incidents),
+	})
+}
+
+func (h *Handler) GetIncident(c *gin.Context) {
+	id, ok := incidentID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	incident, err := h.db.GetIncident(ctx, id)
+	if err != nil {
+		respondIncidentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+// AcknowledgeIncident marks an incident as being handled, which stops its
+// reminder notifications.
+func (h *Handler) AcknowledgeIncident(c *gin.Context) {
+	id, note, ok := incidentRequest(c, false)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	incident, err := h.db.AcknowledgeIncident(ctx, id, note.Author, note.Message)
+	if err != nil {
+		respondIncidentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+// CommentOnIncident adds a comment to an incident's timeline. Resolved
+// incidents accept comments too, e.g. for follow-ups.
+func (h *Handler) CommentOnIncident(c *gin.Context) {
+	id, note, ok := incidentRequest(c, true)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	incident, err := h.db.AddIncidentEvent(ctx, id, models.IncidentEvent{
+		Type:      "comment",
+		Timestamp: time.Now(),
+		Message:   note.Message,
+		Author:    note.Author,
+	})
+	if err != nil {
+		respondIncidentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+// ResolveIncident resolves an incident by hand and closes the monitor's open
+// outage alert.
+func (h *Handler) ResolveIncident(c *gin.Context) {
+	id, note, ok := incidentRequest(c, false)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	incident, err := h.monitor.ResolveIncident(ctx, id, note.Author, note.Message)
+	if err != nil {
+		respondIncidentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+func incidentID(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incident id"})
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// incidentRequest reads the incident ID and the optional note of a request.
+// requireMessage rejects notes without a message.
+func incidentRequest(c *gin.Context, requireMessage bool) (primitive.ObjectID, incidentNote, bool) {
+	var note incidentNote
+
+	id, ok := incidentID(c)
+	if !ok {
+		return id, note, false
+	}
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&note); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return id, note, false
+		}
+	}
+
+	note.Author = strings.TrimSpace(note.Author)
+	note.Message = strings.TrimSpace(note.Message)
+	if requireMessage && note.Message == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "message is required"})
+		return id, note, false
+	}
+
+	return id, note, true
+}
+
+func respondIncidentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, database.ErrIncidentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, database.ErrIncidentResolved):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+	"railway-api-uptime-monitor/internal/database"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (h *Handler) ListMonitors(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	monitors, err := h.db.ListMonitors(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"monitors": monitors,
+		"count":    len(monitors),
+	})
+}
+
+func (h *Handler) GetMonitor(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	monitor, err := h.db.GetMonitor(ctx, c.Param("name"))
+	if err != nil {
+		respondMonitorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, monitor)
+}
+
+func (h *Handler) CreateMonitor(c *gin.Context) {
+	var monitor config.APIConfig
+	if err := c.ShouldBindJSON(&monitor); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	monitor.ApplyDefaults()
+	if err := monitor.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.monitor.CheckNotifyRules(monitor); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.db.CreateMonitor(ctx, &monitor); err != nil {
+		respondMonitorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, monitor)
+}
+
+// UpdateMonitor replaces a monitor definition. Fields missing from the body
+// fall back to their defaults.
+func (h *Handler) UpdateMonitor(c *gin.Context) {
+	name := c.Param("name")
+
+	var monitor config.APIConfig
+	if err := c.ShouldBindJSON(&monitor); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.saveMonitor(c, name, &monitor)
+}
+
+// PatchMonitor merges the fields present in the body into the stored
+// monitor definition.
+func (h *Handler) PatchMonitor(c *gin.Context) {
+	name := c.Param("name")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	monitor, err := h.db.GetMonitor(ctx, name)
+	if err != nil {
+		respondMonitorError(c, err)
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := json.Unmarshal(body, monitor); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.saveMonitor(c, name, monitor)
+}
+
+func (h *Handler) saveMonitor(c *gin.Context, name string, monitor *config.APIConfig) {
+	if monitor.Name == "" {
+		monitor.Name = name
+	}
+	if monitor.Name != name {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "monitor name cannot be changed"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Keep the heartbeat URL of a push monitor when the body omits its token.
+	if strings.EqualFold(strings.TrimSpace(monitor.Type), config.TypePush) && (monitor.Push == nil || monitor.Push.Token == "") {
+		if stored, err := h.db.GetMonitor(ctx, name); err == nil && stored.Push != nil {
+			if monitor.Push == nil {
+				monitor.Push = &config.PushConfig{}
+			}
+			monitor.Push.Token = stored.Push.Token
+		}
+	}
+
+	monitor.ApplyDefaults()
+	if err := monitor.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.monitor.CheckNotifyRules(*monitor); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.db.ReplaceMonitor(ctx, name, monitor); err != nil {
+		respondMonitorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, monitor)
+}
+
+func (h *Handler) DeleteMonitor(c *gin.Context) {
+	name := c.Param("name")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.db.DeleteMonitor(ctx, name); err != nil {
+		respondMonitorError(c, err)
+		return
+	}
+
+	// Close its incident and alerts and drop the current status so the
+	// dashboard stops showing the monitor. Health check history is kept.
+	if err := h.monitor.CloseDeleted(ctx, name); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	_, err := h.db.GetCollection("api_status").DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func respondMonitorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, database.ErrMonitorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, database.ErrMonitorExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// ListChannels returns the names of the notification channels monitors can
+// refer to in their notify rules.
+func (h *Handler) ListChannels(c *gin.Context) {
+	channels := h.monitor.Channels()
+
+	c.JSON(http.StatusOK, gin.H{
+		"channels": channels,
+		"count":    len(channels),
+	})
+}
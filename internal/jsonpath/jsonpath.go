@@ -0,0 +1,140 @@
+// Package jsonpath implements the subset of JSONPath used by monitor
+// assertions: dotted member access and array indexes, e.g. "$.data.items[0].id".
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type segment struct {
+	key   string
+	index int
+	isIdx bool
+}
+
+// Compile checks that path is well formed.
+func Compile(path string) error {
+	_, err := parse(path)
+	return err
+}
+
+// Lookup evaluates path against a decoded JSON document. The second return
+// value is false when the path does not exist in the document.
+func Lookup(doc interface{}, path string) (interface{}, bool, error) {
+	segments, err := parse(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	current := doc
+	for _, seg := range segments {
+		if seg.isIdx {
+			arr, ok := current.([]interface{})
+			if !ok || seg.index < 0 || seg.index >= len(arr) {
+				return nil, false, nil
+			}
+			current = arr[seg.index]
+			continue
+		}
+
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false, nil
+		}
+		current, ok = obj[seg.key]
+		if !ok {
+			return nil, false, nil
+		}
+	}
+
+	return current, true, nil
+}
+
+// LookupBytes decodes body as JSON and evaluates path against it. Numbers
+// are decoded as json.Number, so integers beyond the precision of a float64,
+// such as 64-bit IDs, keep their digits.
+func LookupBytes(body []byte, path string) (interface{}, bool, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, false, fmt.Errorf("response is not valid JSON: %v", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, false, errors.New("response is not valid JSON: invalid data after top-level value")
+	}
+	return Lookup(doc, path)
+}
+
+// Format renders a looked-up value the way it appears in JSON, except that
+// strings are returned without quotes and numbers as they were written.
+func Format(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
+
+func parse(path string) ([]segment, error) {
+	p := strings.TrimSpace(path)
+	p = strings.TrimPrefix(p, "$")
+	if p == "" {
+		return nil, nil
+	}
+
+	var segments []segment
+	for len(p) > 0 {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+			end := strings.IndexAny(p, ".[")
+			if end == -1 {
+				end = len(p)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path %q: empty member name", path)
+			}
+			segments = append(segments, segment{key: p[:end]})
+			p = p[end:]
+		case '[':
+			end := strings.IndexByte(p, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid path %q: missing ']'", path)
+			}
+			inner := p[1:end]
+			if quoted, err := strconv.Unquote(strings.ReplaceAll(inner, "'", "\"")); err == nil {
+				segments = append(segments, segment{key: quoted})
+			} else {
+				idx, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid path %q: bad index %q", path, inner)
+				}
+				segments = append(segments, segment{index: idx, isIdx: true})
+			}
+			p = p[end+1:]
+		default:
+			// Allow paths written without the leading "$." such as "status".
+			if len(segments) == 0 {
+				p = "." + p
+				continue
+			}
+			return nil, fmt.Errorf("invalid path %q", path)
+		}
+	}
+
+	return segments, nil
+}
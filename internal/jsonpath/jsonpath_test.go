@@ -0,0 +1,119 @@
+package jsonpath
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    []segment
+		wantErr bool
+	}{
+		{path: "$", want: nil},
+		{path: "", want: nil},
+		{path: "  $.status  ", want: []segment{{key: "status"}}},
+		{path: "status", want: []segment{{key: "status"}}},
+		{path: "data.items", want: []segment{{key: "data"}, {key: "items"}}},
+		{path: "$.data.items[0].id", want: []segment{{key: "data"}, {key: "items"}, {index: 0, isIdx: true}, {key: "id"}}},
+		{path: "$[2]", want: []segment{{index: 2, isIdx: true}}},
+		{path: "$.matrix[1][0]", want: []segment{{key: "matrix"}, {index: 1, isIdx: true}, {index: 0, isIdx: true}}},
+		{path: "$['a b']", want: []segment{{key: "a b"}}},
+		{path: `$["a b"]`, want: []segment{{key: "a b"}}},
+		{path: "$['a.b'].c", want: []segment{{key: "a.b"}, {key: "c"}}},
+		{path: "$['0']", want: []segment{{key: "0"}}},
+		{path: "$.items[-1]", want: []segment{{key: "items"}, {index: -1, isIdx: true}}},
+		{path: "$.", wantErr: true},
+		{path: "$..a", wantErr: true},
+		{path: "$.a.", wantErr: true},
+		{path: "$.items[0", wantErr: true},
+		{path: "$.items[x]", wantErr: true},
+		{path: "$.items[]", wantErr: true},
+		{path: "$.items[0]x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := parse(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parse(%q) = %+v, want an error", tt.path, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse(%q): %v", tt.path, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parse(%q) = %+v, want %+v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupBytes(t *testing.T) {
+	body := []byte(`{
+		"status": "ok",
+		"count": 3,
+		"ratio": 0.5,
+		"id": 12345678901234567890,
+		"exponent": 1e3,
+		"healthy": true,
+		"missing": null,
+		"a b": {"c": 1},
+		"data": {"items": [{"id": "first"}, {"id": "second"}]}
+	}`)
+
+	tests := []struct {
+		path      string
+		want      string
+		wantFound bool
+	}{
+		{path: "$.status", want: "ok", wantFound: true},
+		{path: "$.count", want: "3", wantFound: true},
+		{path: "$.ratio", want: "0.5", wantFound: true},
+		{path: "$.id", want: "12345678901234567890", wantFound: true},
+		{path: "$.exponent", want: "1e3", wantFound: true},
+		{path: "$.healthy", want: "true", wantFound: true},
+		{path: "$.missing", want: "null", wantFound: true},
+		{path: "$['a b'].c", want: "1", wantFound: true},
+		{path: "$.data.items[1].id", want: "second", wantFound: true},
+		{path: "$.data.items[0]", want: `{"id":"first"}`, wantFound: true},
+		{path: "$.nope"},
+		{path: "$.data.items[2].id"},
+		{path: "$.data.items[-1]"},
+		{path: "$.status.value"},
+		{path: "$.data[0]"},
+		{path: "$.data.items.id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			value, found, err := LookupBytes(body, tt.path)
+			if err != nil {
+				t.Fatalf("LookupBytes(%q): %v", tt.path, err)
+			}
+			if found != tt.wantFound {
+				t.Fatalf("LookupBytes(%q) found = %v, want %v", tt.path, found, tt.wantFound)
+			}
+			if found {
+				if got := Format(value); got != tt.want {
+					t.Errorf("LookupBytes(%q) = %s, want %s", tt.path, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLookupBytesInvalidJSON(t *testing.T) {
+	if _, _, err := LookupBytes([]byte("<html>"), "$.status"); err == nil {
+		t.Error("LookupBytes on HTML returned no error")
+	}
+	if _, _, err := LookupBytes([]byte(`{"status": "ok"} {}`), "$.status"); err == nil {
+		t.Error("LookupBytes with trailing data returned no error")
+	}
+	if _, _, err := LookupBytes([]byte(`{}`), "$.items[x]"); err == nil {
+		t.Error("LookupBytes with a bad path returned no error")
+	}
+}
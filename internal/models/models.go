@@ -7,37 +7,160 @@ import (
 )
 
 type APIStatus struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name            string             `bson:"name" json:"name"`
+	Type            string             `bson:"type,omitempty" json:"type,omitempty"`
+	URL             string             `bson:"url" json:"url"`
+	Method          string             `bson:"method" json:"method"`
+	Status          string             `bson:"status" json:"status"`                         // "up", "degraded", "down", "timeout", "unknown"
+	Severity        string             `bson:"severity,omitempty" json:"severity,omitempty"` // "warning" or "critical" while degraded
+	StatusCode      int                `bson:"status_code" json:"status_code"`
+	ResponseTime    time.Duration      `bson:"response_time" json:"response_time"`
+	LastChecked     time.Time          `bson:"last_checked" json:"last_checked"`
+	LastUp          time.Time          `bson:"last_up" json:"last_up"`
+	LastDown        time.Time          `bson:"last_down" json:"last_down"`
+	DowntimeCount   int                `bson:"downtime_count" json:"downtime_count"`
+	UptimePercent   float64            `bson:"uptime_percent" json:"uptime_percent"` // up or degraded
+	DegradedPercent float64            `bson:"degraded_percent" json:"degraded_percent"`
+	ErrorMessage    string             `bson:"error_message,omitempty" json:"error_message,omitempty"`
+	TLS             *TLSInfo           `bson:"tls,omitempty" json:"tls,omitempty"`
+	DNSRecords      []string           `bson:"dns_records,omitempty" json:"dns_records,omitempty"`
+	Ping            *PingStats         `bson:"ping,omitempty" json:"ping,omitempty"`
+	LastHeartbeat   time.Time          `bson:"last_heartbeat,omitempty" json:"last_heartbeat,omitempty"`
+	FailedStep      string             `bson:"failed_step,omitempty" json:"failed_step,omitempty"`
+	Content         *ContentInfo       `bson:"content,omitempty" json:"content,omitempty"`
+}
+
+// ContentInfo is the tracked content of a monitor as of its latest successful
+// check.
+type ContentInfo struct {
+	Hash         string    `bson:"hash" json:"hash"`
+	Text         string    `bson:"text" json:"-"` // normalized content, kept to diff the next change
+	KeywordError string    `bson:"keyword_error,omitempty" json:"keyword_error,omitempty"`
+	Error        string    `bson:"error,omitempty" json:"error,omitempty"`
+	CheckedAt    time.Time `bson:"checked_at" json:"checked_at"`
+}
+
+// TLSInfo describes the certificate chain presented by an HTTPS monitor on
+// its latest check.
+type TLSInfo struct {
+	Chain         []CertificateInfo `bson:"chain" json:"chain"`
+	DaysRemaining int               `bson:"days_remaining" json:"days_remaining"`
+	VerifyError   string            `bson:"verify_error,omitempty" json:"verify_error,omitempty"`
+	CheckedAt     time.Time         `bson:"checked_at" json:"checked_at"`
+
+	// AlertedThreshold is the smallest expiry threshold, in days, already
+	// alerted for this certificate.
+	AlertedThreshold int `bson:"alerted_threshold,omitempty" json:"alerted_threshold,omitempty"`
+}
+
+type CertificateInfo struct {
+	Subject  string    `bson:"subject" json:"subject"`
+	Issuer   string    `bson:"issuer" json:"issuer"`
+	SANs     []string  `bson:"sans,omitempty" json:"sans,omitempty"`
+	NotAfter time.Time `bson:"not_after" json:"not_after"`
+}
+
+// PingStats summarizes the probes of a "ping" monitor check.
+type PingStats struct {
+	Sent        int           `bson:"sent" json:"sent"`
+	Received    int           `bson:"received" json:"received"`
+	LossPercent float64       `bson:"loss_percent" json:"loss_percent"`
+	MinLatency  time.Duration `bson:"min_latency" json:"min_latency"`
+	AvgLatency  time.Duration `bson:"avg_latency" json:"avg_latency"`
+	MaxLatency  time.Duration `bson:"max_latency" json:"max_latency"`
+}
+
+type HealthCheck struct {
 	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name          string             `bson:"name" json:"name"`
+	APIName       string             `bson:"api_name" json:"api_name"`
 	URL           string             `bson:"url" json:"url"`
-	Method        string             `bson:"method" json:"method"`
-	Status        string             `bson:"status" json:"status"` // "up", "down", "unknown"
+	Status        string             `bson:"status" json:"status"` // "up", "degraded", "down", "timeout"
 	StatusCode    int                `bson:"status_code" json:"status_code"`
+	StatusRule    string             `bson:"status_rule,omitempty" json:"status_rule,omitempty"` // expected status rule the code matched
 	ResponseTime  time.Duration      `bson:"response_time" json:"response_time"`
-	LastChecked   time.Time          `bson:"last_checked" json:"last_checked"`
-	LastUp        time.Time          `bson:"last_up" json:"last_up"`
-	LastDown      time.Time          `bson:"last_down" json:"last_down"`
-	DowntimeCount int                `bson:"downtime_count" json:"downtime_count"`
-	UptimePercent float64            `bson:"uptime_percent" json:"uptime_percent"`
+	Timestamp     time.Time          `bson:"timestamp" json:"timestamp"`
 	ErrorMessage  string             `bson:"error_message,omitempty" json:"error_message,omitempty"`
+	Attempts      int                `bson:"attempts" json:"attempts"`
+	AttemptErrors []string           `bson:"attempt_errors,omitempty" json:"attempt_errors,omitempty"`
+	DNSRecords    []string           `bson:"dns_records,omitempty" json:"dns_records,omitempty"`
+	Ping          *PingStats         `bson:"ping,omitempty" json:"ping,omitempty"`
+	Log           string             `bson:"log,omitempty" json:"log,omitempty"` // sent with a push heartbeat
+	Steps         []StepResult       `bson:"steps,omitempty" json:"steps,omitempty"`
+
+	// Phase timings of the last attempt. Phases skipped on a reused
+	// connection are omitted.
+	DNSLookup       time.Duration `bson:"dns_lookup,omitempty" json:"dns_lookup,omitempty"`
+	TCPConnect      time.Duration `bson:"tcp_connect,omitempty" json:"tcp_connect,omitempty"`
+	TLSHandshake    time.Duration `bson:"tls_handshake,omitempty" json:"tls_handshake,omitempty"`
+	TimeToFirstByte time.Duration `bson:"time_to_first_byte,omitempty" json:"time_to_first_byte,omitempty"`
+	ContentTransfer time.Duration `bson:"content_transfer,omitempty" json:"content_transfer,omitempty"`
+
+	// WebSocket upgrade handshake and message round trip.
+	Handshake time.Duration `bson:"handshake,omitempty" json:"handshake,omitempty"`
+	RoundTrip time.Duration `bson:"round_trip,omitempty" json:"round_trip,omitempty"`
 }
 
-type HealthCheck struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	APIName      string             `bson:"api_name" json:"api_name"`
-	URL          string             `bson:"url" json:"url"`
-	Status       string             `bson:"status" json:"status"`
-	StatusCode   int                `bson:"status_code" json:"status_code"`
-	ResponseTime time.Duration      `bson:"response_time" json:"response_time"`
-	Timestamp    time.Time          `bson:"timestamp" json:"timestamp"`
-	ErrorMessage string             `bson:"error_message,omitempty" json:"error_message,omitempty"`
+// StepResult is the outcome of one request of a multi-step check. Steps after
+// a failed one are not run.
+type StepResult struct {
+	Name         string        `bson:"name" json:"name"`
+	Status       string        `bson:"status" json:"status"`
+	StatusCode   int           `bson:"status_code" json:"status_code"`
+	ResponseTime time.Duration `bson:"response_time" json:"response_time"`
+	ErrorMessage string        `bson:"error_message,omitempty" json:"error_message,omitempty"`
 }
 
 type Alert struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	APIName   string             `bson:"api_name" json:"api_name"`
-	Type      string             `bson:"type" json:"type"` // "down", "up", "timeout"
+	Type      string             `bson:"type" json:"type"`         // "down", "up", "timeout", "certificate", "dns_change", "content_change", "keyword", "degraded"
+	Severity  string             `bson:"severity" json:"severity"` // "info", "warning" or "critical"
 	Message   string             `bson:"message" json:"message"`
 	Timestamp time.Time          `bson:"timestamp" json:"timestamp"`
 	Resolved  bool               `bson:"resolved" json:"resolved"`
+	Diff      string             `bson:"diff,omitempty" json:"diff,omitempty"`
+
+	// Outage and degraded alerts stay open while the condition lasts and
+	// are updated in place.
+	UpdatedAt      time.Time     `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+	ResolvedAt     *time.Time    `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+	Duration       time.Duration `bson:"duration,omitempty" json:"duration,omitempty"`
+	LastNotifiedAt time.Time     `bson:"last_notified_at,omitempty" json:"last_notified_at,omitempty"`
+	Notifications  int           `bson:"notifications,omitempty" json:"notifications,omitempty"`
 }
+
+// Incident covers an outage of a monitor, from its first failed check to its
+// recovery.
+type Incident struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	APIName        string             `bson:"api_name" json:"api_name"`
+	Status         string             `bson:"status" json:"status"` // "open", "acknowledged" or "resolved"
+	Summary        string             `bson:"summary" json:"summary"`
+	StartedAt      time.Time          `bson:"started_at" json:"started_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+	FailedChecks   int                `bson:"failed_checks" json:"failed_checks"`
+	AcknowledgedAt *time.Time         `bson:"acknowledged_at,omitempty" json:"acknowledged_at,omitempty"`
+	AcknowledgedBy string             `bson:"acknowledged_by,omitempty" json:"acknowledged_by,omitempty"`
+	ResolvedAt     *time.Time         `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+	ResolvedBy     string             `bson:"resolved_by,omitempty" json:"resolved_by,omitempty"`
+	Duration       time.Duration      `bson:"duration,omitempty" json:"duration,omitempty"`
+	Timeline       []IncidentEvent    `bson:"timeline" json:"timeline"`
+}
+
+type IncidentEvent struct {
+	Type       string              `bson:"type" json:"type"` // "check", "notification", "acknowledged", "comment" or "resolved"
+	Timestamp  time.Time           `bson:"timestamp" json:"timestamp"`
+	Message    string              `bson:"message,omitempty" json:"message,omitempty"`
+	Author     string              `bson:"author,omitempty" json:"author,omitempty"`
+	CheckID    *primitive.ObjectID `bson:"check_id,omitempty" json:"check_id,omitempty"`
+	Status     string              `bson:"status,omitempty" json:"status,omitempty"`
+	StatusCode int                 `bson:"status_code,omitempty" json:"status_code,omitempty"`
+}
+
+// Incident statuses.
+const (
+	IncidentOpen         = "open"
+	IncidentAcknowledged = "acknowledged"
+	IncidentResolved     = "resolved"
+)
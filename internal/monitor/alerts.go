@@ -0,0 +1,221 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+	"railway-api-uptime-monitor/internal/models"
+	"railway-api-uptime-monitor/internal/webhook"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Alert types that describe a state rather than an event. A monitor has at
+// most one open alert of each group, updated in place until it resolves.
+var (
+	outageAlertTypes   = []string{"down", "timeout"}
+	degradedAlertTypes = []string{"degraded"}
+)
+
+// openAlert opens an alert of the given group for the monitor, or updates
+// the one already open. A new alert is notified right away; an open one is
+// notified again when its severity changes or its reminder interval has
+// passed, unless incident, the outage it belongs to, was acknowledged. It
+// returns the notification sent, if any.
+func (m *Monitor) openAlert(apiConfig config.APIConfig, group []string, alert models.Alert, incident *models.Incident) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := m.db.GetCollection("alerts")
+	now := time.Now()
+
+	var open models.Alert
+	err := collection.FindOne(ctx, openAlertFilter(alert.APIName, group)).Decode(&open)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		alert.Timestamp = now
+		alert.UpdatedAt = now
+		alert.LastNotifiedAt = now
+		alert.Notifications = 1
+		m.storeAndNotify(apiConfig, alert, incident)
+		return alert.Message
+	}
+	if err != nil {
+		log.Printf("Error loading open alert: %v", err)
+		return ""
+	}
+
+	set := bson.M{
+		"type":       alert.Type,
+		"severity":   alert.Severity,
+		"message":    alert.Message,
+		"updated_at": now,
+	}
+
+	reminder := m.reminderInterval(apiConfig)
+	if incident != nil && incident.Status == models.IncidentAcknowledged {
+		reminder = 0
+	}
+	notify := alert.Severity != open.Severity ||
+		(reminder > 0 && now.Sub(open.LastNotifiedAt) >= reminder)
+	if notify {
+		set["last_notified_at"] = now
+	}
+
+	update := bson.M{"$set": set}
+	if notify {
+		update["$inc"] = bson.M{"notifications": 1}
+	}
+	if _, err := collection.UpdateByID(ctx, open.ID, update); err != nil {
+		log.Printf("Error updating alert: %v", err)
+	}
+
+	if !notify {
+		return ""
+	}
+
+	alert.ID = open.ID
+	alert.Timestamp = open.Timestamp
+	alert.UpdatedAt = now
+	alert.LastNotifiedAt = now
+	alert.Notifications = open.Notifications + 1
+
+	message := fmt.Sprintf("%s (ongoing for %s)", alert.Message, formatDuration(now.Sub(open.Timestamp)))
+	m.notify(apiConfig, webhook.Event{
+		APIName:  alert.APIName,
+		Type:     alert.Type,
+		Severity: alert.Severity,
+		Message:  message,
+		Alert:    &alert,
+		Incident: incident,
+	})
+	return message
+}
+
+// resolveAlert closes the monitor's open alert of the given group, if any,
+// recording when it resolved and how long it lasted, and sends a
+// notification of eventType, "up" for a recovery, including incident if the
+// alert belongs to one. It returns the notification sent, if any.
+func (m *Monitor) resolveAlert(apiConfig config.APIConfig, group []string, eventType, message string, incident *models.Incident) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := m.db.GetCollection("alerts")
+	now := time.Now()
+
+	var open models.Alert
+	err := collection.FindOne(ctx, openAlertFilter(apiConfig.Name, group)).Decode(&open)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ""
+	}
+	if err != nil {
+		log.Printf("Error loading open alert: %v", err)
+		return ""
+	}
+
+	duration := now.Sub(open.Timestamp)
+	_, err = collection.UpdateByID(ctx, open.ID, bson.M{"$set": bson.M{
+		"resolved":    true,
+		"resolved_at": now,
+		"duration":    duration,
+		"updated_at":  now,
+	}})
+	if err != nil {
+		log.Printf("Error resolving alert: %v", err)
+		return ""
+	}
+
+	open.Resolved = true
+	open.ResolvedAt = &now
+	open.Duration = duration
+	open.UpdatedAt = now
+
+	message = fmt.Sprintf("%s after %s", message, formatDuration(duration))
+	m.notify(apiConfig, webhook.Event{
+		APIName:  apiConfig.Name,
+		Type:     eventType,
+		Severity: severityInfo,
+		Message:  message,
+		Alert:    &open,
+		Incident: incident,
+	})
+	return message
+}
+
+func openAlertFilter(apiName string, group []string) bson.M {
+	return bson.M{
+		"api_name": apiName,
+		"type":     bson.M{"$in": group},
+		"resolved": false,
+	}
+}
+
+// reminderInterval returns how often an open alert is notified again; zero
+// disables reminders.
+func (m *Monitor) reminderInterval(apiConfig config.APIConfig) time.Duration {
+	if apiConfig.ReminderInterval != "" {
+		d, err := time.ParseDuration(apiConfig.ReminderInterval)
+		if err == nil {
+			return d
+		}
+	}
+	return time.Duration(m.config.AlertReminderMinutes) * time.Minute
+}
+
+// downtimeThreshold returns how many consecutive failed checks open an
+// outage alert. Push monitors alert on the first one: a missed heartbeat is
+// only counted once per period plus grace, and a job reporting a failure
+// has already failed.
+func (m *Monitor) downtimeThreshold(apiConfig config.APIConfig) int {
+	if apiConfig.Type == config.TypePush {
+		return 1
+	}
+	return m.config.DowntimeThreshold
+}
+
+// outageAlert returns the alert for a failed check that brings the
+// monitor's consecutive failures to downtimeCount, or false while the count
+// is below the monitor's threshold.
+func (m *Monitor) outageAlert(apiConfig config.APIConfig, result checkResult, downtimeCount int) (models.Alert, bool) {
+	if downtimeCount < m.downtimeThreshold(apiConfig) {
+		return models.Alert{}, false
+	}
+
+	alert := models.Alert{
+		APIName:  apiConfig.Name,
+		Type:     "down",
+		Severity: severityCritical,
+		Message:  fmt.Sprintf("API has been down for %d consecutive checks", downtimeCount),
+	}
+	if result.Status == "timeout" {
+		alert.Type = "timeout"
+		alert.Message = fmt.Sprintf("API has failed %d consecutive checks, the last one timed out", downtimeCount)
+	}
+	if apiConfig.Type == config.TypePush && result.Err != nil {
+		alert.Message = result.Err.Error()
+	}
+	return alert, true
+}
+
+// formatDuration rounds d for display in notifications.
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return d.Round(time.Minute).String()
+}
+
+// CheckNotifyRules reports notify rules of a monitor that name an unknown
+// channel.
+func (m *Monitor) CheckNotifyRules(apiConfig config.APIConfig) error {
+	return m.notifier.CheckRules(apiConfig.Notify)
+}
+
+// Channels returns the names of the notification channels.
+func (m *Monitor) Channels() []string {
+	return m.notifier.Channels()
+}
@@ -0,0 +1,113 @@
+package monitor
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"railway-api-uptime-monitor/internal/config"
+	"railway-api-uptime-monitor/internal/jsonpath"
+)
+
+// maxBodyBytes caps how much of a response body is read for assertions.
+const maxBodyBytes = 1 << 20
+
+// evaluateAssertions runs the assertions in order and returns an error
+// describing the first one that fails.
+func evaluateAssertions(assertions []config.Assertion, header http.Header, body []byte) error {
+	for i, assertion := range assertions {
+		if err := evaluateAssertion(assertion, header, body); err != nil {
+			return fmt.Errorf("assertion %d (%s) failed: %v", i+1, assertion, err)
+		}
+	}
+	return nil
+}
+
+func evaluateAssertion(assertion config.Assertion, header http.Header, body []byte) error {
+	switch assertion.Type {
+	case config.AssertBodyContains:
+		if !strings.Contains(string(body), assertion.Value) {
+			return fmt.Errorf("body does not contain %q", assertion.Value)
+		}
+
+	case config.AssertBodyRegex:
+		re, err := regexp.Compile(assertion.Value)
+		if err != nil {
+			return err
+		}
+		if !re.Match(body) {
+			return fmt.Errorf("body does not match %q", assertion.Value)
+		}
+
+	case config.AssertJSONPathExists:
+		_, found, err := jsonpath.LookupBytes(body, assertion.Path)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("%s not found", assertion.Path)
+		}
+
+	case config.AssertJSONPathEquals:
+		value, found, err := jsonpath.LookupBytes(body, assertion.Path)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("%s not found", assertion.Path)
+		}
+		if got := jsonpath.Format(value); got != assertion.Value {
+			return fmt.Errorf("got %q", got)
+		}
+
+	case config.AssertJSONPathCompare:
+		value, found, err := jsonpath.LookupBytes(body, assertion.Path)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("%s not found", assertion.Path)
+		}
+		got, err := strconv.ParseFloat(jsonpath.Format(value), 64)
+		if err != nil {
+			return fmt.Errorf("%s is not a number: %s", assertion.Path, jsonpath.Format(value))
+		}
+		want, err := strconv.ParseFloat(assertion.Value, 64)
+		if err != nil {
+			return err
+		}
+		if !compare(got, assertion.Operator, want) {
+			return fmt.Errorf("got %v", got)
+		}
+
+	case config.AssertHeaderEquals:
+		if got := header.Get(assertion.Path); got != assertion.Value {
+			return fmt.Errorf("header %s is %q", assertion.Path, got)
+		}
+
+	default:
+		return fmt.Errorf("unknown assertion type %q", assertion.Type)
+	}
+
+	return nil
+}
+
+func compare(got float64, operator string, want float64) bool {
+	switch operator {
+	case "eq":
+		return got == want
+	case "ne":
+		return got != want
+	case "lt":
+		return got < want
+	case "lte":
+		return got <= want
+	case "gt":
+		return got > want
+	case "gte":
+		return got >= want
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package monitor
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"railway-api-uptime-monitor/internal/config"
+)
+
+func TestEvaluateAssertions(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("X-Version", "2")
+	body := []byte(`{"status":"ok","count":3,"latency":"12.5","name":"orders","items":[{"id":7}],"a b":true,"user_id":12345678901234567890}`)
+
+	tests := []struct {
+		name       string
+		assertions []config.Assertion
+		wantErr    string
+	}{
+		{name: "none"},
+		{
+			name:       "body contains",
+			assertions: []config.Assertion{{Type: config.AssertBodyContains, Value: `"status":"ok"`}},
+		},
+		{
+			name:       "body does not contain",
+			assertions: []config.Assertion{{Type: config.AssertBodyContains, Value: "error"}},
+			wantErr:    `assertion 1 (body_contains "error") failed: body does not contain "error"`,
+		},
+		{
+			name:       "body regex",
+			assertions: []config.Assertion{{Type: config.AssertBodyRegex, Value: `"count":\d+`}},
+		},
+		{
+			name:       "body regex mismatch",
+			assertions: []config.Assertion{{Type: config.AssertBodyRegex, Value: `"count":"\d+"`}},
+			wantErr:    "body does not match",
+		},
+		{
+			name:       "jsonpath exists",
+			assertions: []config.Assertion{{Type: config.AssertJSONPathExists, Path: "$.items[0].id"}},
+		},
+		{
+			name:       "jsonpath exists with bracket member",
+			assertions: []config.Assertion{{Type: config.AssertJSONPathExists, Path: "$['a b']"}},
+		},
+		{
+			name:       "jsonpath missing",
+			assertions: []config.Assertion{{Type: config.AssertJSONPathExists, Path: "$.items[1]"}},
+			wantErr:    "$.items[1] not found",
+		},
+		{
+			name:       "jsonpath equals string",
+			assertions: []config.Assertion{{Type: config.AssertJSONPathEquals, Path: "$.status", Value: "ok"}},
+		},
+		{
+			name:       "jsonpath equals number",
+			assertions: []config.Assertion{{Type: config.AssertJSONPathEquals, Path: "$.count", Value: "3"}},
+		},
+		{
+			name:       "jsonpath equals 64-bit integer",
+			assertions: []config.Assertion{{Type: config.AssertJSONPathEquals, Path: "$.user_id", Value: "12345678901234567890"}},
+		},
+		{
+			name:       "jsonpath equals mismatch",
+			assertions: []config.Assertion{{Type: config.AssertJSONPathEquals, Path: "$.status", Value: "degraded"}},
+			wantErr:    `got "ok"`,
+		},
+		{
+			name:       "compare number",
+			assertions: []config.Assertion{{Type: config.AssertJSONPathCompare, Path: "$.count", Operator: "gte", Value: "3"}},
+		},
+		{
+			name:       "compare number fails",
+			assertions: []config.Assertion{{Type: config.AssertJSONPathCompare, Path: "$.count", Operator: "lt", Value: "3"}},
+			wantErr:    "got 3",
+		},
+		{
+			name:       "compare numeric string",
+			assertions: []config.Assertion{{Type: config.AssertJSONPathCompare, Path: "$.latency", Operator: "lt", Value: "100"}},
+		},
+		{
+			name:       "compare non-numeric string",
+			assertions: []config.Assertion{{Type: config.AssertJSONPathCompare, Path: "$.name", Operator: "gt", Value: "1"}},
+			wantErr:    "$.name is not a number: orders",
+		},
+		{
+			name:       "compare object",
+			assertions: []config.Assertion{{Type: config.AssertJSONPathCompare, Path: "$.items", Operator: "eq", Value: "1"}},
+			wantErr:    "is not a number",
+		},
+		{
+			name:       "compare missing path",
+			assertions: []config.Assertion{{Type: config.AssertJSONPathCompare, Path: "$.nope", Operator: "eq", Value: "1"}},
+			wantErr:    "$.nope not found",
+		},
+		{
+			name:       "header equals",
+			assertions: []config.Assertion{{Type: config.AssertHeaderEquals, Path: "x-version", Value: "2"}},
+		},
+		{
+			name:       "header mismatch",
+			assertions: []config.Assertion{{Type: config.AssertHeaderEquals, Path: "X-Version", Value: "3"}},
+			wantErr:    `header X-Version is "2"`,
+		},
+		{
+			name:       "missing header",
+			assertions: []config.Assertion{{Type: config.AssertHeaderEquals, Path: "X-Missing", Value: ""}},
+		},
+		{
+			name: "first failure is reported",
+			assertions: []config.Assertion{
+				{Type: config.AssertBodyContains, Value: "orders"},
+				{Type: config.AssertJSONPathEquals, Path: "$.count", Value: "4"},
+				{Type: config.AssertBodyContains, Value: "error"},
+			},
+			wantErr: "assertion 2 (jsonpath_equals $.count == \"4\") failed",
+		},
+		{
+			name:       "unknown type",
+			assertions: []config.Assertion{{Type: "body_length"}},
+			wantErr:    `unknown assertion type "body_length"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := evaluateAssertions(tt.assertions, header, body)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("evaluateAssertions: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("evaluateAssertions = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluateAssertionsNonJSONBody(t *testing.T) {
+	assertions := []config.Assertion{{Type: config.AssertJSONPathExists, Path: "$.status"}}
+
+	err := evaluateAssertions(assertions, http.Header{}, []byte("<html>ok</html>"))
+	if err == nil || !strings.Contains(err.Error(), "response is not valid JSON") {
+		t.Errorf("evaluateAssertions = %v, want a JSON error", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		got      float64
+		operator string
+		want     float64
+		result   bool
+	}{
+		{1, "eq", 1, true},
+		{1, "eq", 2, false},
+		{1, "ne", 2, true},
+		{1, "lt", 2, true},
+		{2, "lt", 2, false},
+		{2, "lte", 2, true},
+		{3, "gt", 2, true},
+		{2, "gt", 2, false},
+		{2, "gte", 2, true},
+		{1, "like", 1, false},
+	}
+
+	for _, tt := range tests {
+		if got := compare(tt.got, tt.operator, tt.want); got != tt.result {
+			t.Errorf("compare(%v, %s, %v) = %v, want %v", tt.got, tt.operator, tt.want, got, tt.result)
+		}
+	}
+}
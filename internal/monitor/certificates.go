@@ -0,0 +1,170 @@
+package monitor
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+	"railway-api-uptime-monitor/internal/models"
+)
+
+// newHTTPClient returns the client used for HTTP checks. Certificates are
+// verified by crypto/tls against rootCAs, or the system roots when rootCAs is
+// nil: net/http sets each connection's dialed host as the ServerName, which
+// covers redirects, proxies and IP-literal hosts. The peer chain of a failed
+// verification is recovered from the error by failedTLSInfo.
+func (m *Monitor) newHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: m.rootCAs}
+
+	return &http.Client{Transport: transport}
+}
+
+// verifyPeerChain returns a VerifyConnection callback performing the standard
+// chain and hostname verification for host against rootCAs, or the system
+// roots when rootCAs is nil. It is used where crypto/tls would not report the
+// chain of a failed verification. The dialed host is passed in because
+// ConnectionState.ServerName is empty for IP-literal hosts.
+func (m *Monitor) verifyPeerChain(host string) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("tls: server presented no certificates")
+		}
+
+		opts := x509.VerifyOptions{
+			DNSName:       host,
+			Roots:         m.rootCAs,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+
+		if _, err := cs.PeerCertificates[0].Verify(opts); err != nil {
+			return &certVerifyError{err: err, chain: cs.PeerCertificates}
+		}
+		return nil
+	}
+}
+
+// certVerifyError carries the peer chain of a connection that failed
+// verifyPeerChain.
+type certVerifyError struct {
+	err   error
+	chain []*x509.Certificate
+}
+
+func (e *certVerifyError) Error() string { return e.err.Error() }
+
+func (e *certVerifyError) Unwrap() error { return e.err }
+
+// tlsInfo summarizes a peer certificate chain. verifyErr is the verification
+// failure, if any.
+func tlsInfo(certs []*x509.Certificate, verifyErr error) *models.TLSInfo {
+	if len(certs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	info := &models.TLSInfo{
+		DaysRemaining: int(math.Floor(certs[0].NotAfter.Sub(now).Hours() / 24)),
+		CheckedAt:     now,
+	}
+	for _, cert := range certs {
+		info.Chain = append(info.Chain, models.CertificateInfo{
+			Subject:  cert.Subject.String(),
+			Issuer:   cert.Issuer.String(),
+			SANs:     cert.DNSNames,
+			NotAfter: cert.NotAfter,
+		})
+	}
+	if verifyErr != nil {
+		info.VerifyError = verifyErr.Error()
+	}
+
+	return info
+}
+
+// certExpiryThresholds returns the monitor's expiry thresholds in days,
+// smallest first.
+func (m *Monitor) certExpiryThresholds(apiConfig config.APIConfig) []int {
+	thresholds := m.config.CertExpiryDays
+	if len(apiConfig.CertExpiryDays) > 0 {
+		thresholds = apiConfig.CertExpiryDays
+	}
+
+	sorted := append([]int(nil), thresholds...)
+	sort.Ints(sorted)
+	return sorted
+}
+
+// failedTLSInfo extracts the chain from a check error caused by a failed
+// certificate verification.
+func failedTLSInfo(err error) *models.TLSInfo {
+	var verifyErr *certVerifyError
+	if errors.As(err, &verifyErr) {
+		return tlsInfo(verifyErr.chain, verifyErr.err)
+	}
+
+	var certErr *tls.CertificateVerificationError
+	if errors.As(err, &certErr) {
+		return tlsInfo(certErr.UnverifiedCertificates, certErr.Err)
+	}
+	return nil
+}
+
+// checkCertificate sends the certificate alerts reported by
+// certificateAlerts.
+func (m *Monitor) checkCertificate(apiConfig config.APIConfig, previous, current *models.TLSInfo) {
+	for _, message := range m.certificateAlerts(apiConfig, previous, current) {
+		m.sendAlert(apiConfig, "certificate", message)
+	}
+}
+
+// certificateAlerts returns the alert messages due when the certificate
+// crosses an expiry threshold or starts failing verification. Each threshold
+// alerts once; renewing the certificate resets them. It records the alerted
+// threshold on current.
+func (m *Monitor) certificateAlerts(apiConfig config.APIConfig, previous, current *models.TLSInfo) []string {
+	if current == nil {
+		return nil
+	}
+
+	if previous != nil {
+		current.AlertedThreshold = previous.AlertedThreshold
+	}
+
+	crossed := 0
+	for _, threshold := range m.certExpiryThresholds(apiConfig) {
+		if current.DaysRemaining <= threshold {
+			crossed = threshold
+			break
+		}
+	}
+
+	var messages []string
+	if crossed == 0 {
+		current.AlertedThreshold = 0
+	} else if current.AlertedThreshold == 0 || crossed < current.AlertedThreshold {
+		current.AlertedThreshold = crossed
+
+		leaf := current.Chain[0]
+		message := fmt.Sprintf("TLS certificate expires in %d days (%s)", current.DaysRemaining, leaf.NotAfter.Format("2006-01-02"))
+		if current.DaysRemaining < 0 {
+			message = fmt.Sprintf("TLS certificate expired on %s", leaf.NotAfter.Format("2006-01-02"))
+		}
+		messages = append(messages, message)
+	}
+
+	if current.VerifyError != "" && (previous == nil || previous.VerifyError == "") {
+		messages = append(messages, "TLS certificate verification failed: "+current.VerifyError)
+	}
+
+	return messages
+}
@@ -0,0 +1,256 @@
+package monitor
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+	"railway-api-uptime-monitor/internal/models"
+)
+
+// newTestMonitor returns a Monitor without a database that trusts only
+// roots.
+func newTestMonitor(roots ...*x509.Certificate) *Monitor {
+	m := New(nil, nil, &config.Config{
+		TimeoutSeconds: 5,
+		CertExpiryDays: []int{30, 14, 3},
+	})
+	m.rootCAs = x509.NewCertPool()
+	for _, root := range roots {
+		m.rootCAs.AddCert(root)
+	}
+	m.client = m.newHTTPClient()
+	return m
+}
+
+// newTestCertificate returns a self-signed certificate valid only for
+// dnsNames.
+func newTestCertificate(t *testing.T, dnsNames ...string) (tls.Certificate, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:              dnsNames,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, cert
+}
+
+func TestHTTPCheckRecordsChain(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	m := newTestMonitor(server.Certificate())
+	result := m.perform(config.APIConfig{Name: "tls", URL: server.URL, ExpectedStatus: http.StatusOK})
+
+	if result.Status != "up" {
+		t.Fatalf("status = %s (%v), want up", result.Status, result.Err)
+	}
+	if result.TLS == nil || len(result.TLS.Chain) == 0 {
+		t.Fatalf("TLS = %+v, want the peer chain", result.TLS)
+	}
+	leaf := result.TLS.Chain[0]
+	if leaf.Issuer != server.Certificate().Issuer.String() || !leaf.NotAfter.Equal(server.Certificate().NotAfter) {
+		t.Errorf("leaf = %+v, want the server certificate", leaf)
+	}
+	if result.TLS.VerifyError != "" {
+		t.Errorf("VerifyError = %q, want none", result.TLS.VerifyError)
+	}
+}
+
+// TestCertificateVerifyFailure serves a certificate issued for another host
+// on an IP literal and checks every TLS-capable monitor type refuses it and
+// records the chain.
+func TestCertificateVerifyFailure(t *testing.T) {
+	cert, leaf := newTestCertificate(t, "other.example")
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	// The stock test certificate is valid for 127.0.0.1 but not trusted.
+	untrusted := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer untrusted.Close()
+
+	address := server.Listener.Addr().String()
+
+	tests := []struct {
+		name      string
+		roots     []*x509.Certificate
+		apiConfig config.APIConfig
+		wantErr   string
+	}{
+		{
+			name:      "http hostname mismatch",
+			roots:     []*x509.Certificate{leaf},
+			apiConfig: config.APIConfig{URL: server.URL},
+			wantErr:   "for 127.0.0.1",
+		},
+		{
+			name:      "http unknown authority",
+			apiConfig: config.APIConfig{URL: untrusted.URL},
+			wantErr:   "unknown authority",
+		},
+		{
+			name:      "tcp.tls hostname mismatch",
+			roots:     []*x509.Certificate{leaf},
+			apiConfig: config.APIConfig{Type: config.TypeTCP, URL: address, TCP: &config.TCPConfig{TLS: true}},
+			wantErr:   "for 127.0.0.1",
+		},
+		{
+			name:      "websocket hostname mismatch",
+			roots:     []*x509.Certificate{leaf},
+			apiConfig: config.APIConfig{Type: config.TypeWebSocket, URL: "wss://" + address + "/"},
+			wantErr:   "for 127.0.0.1",
+		},
+		{
+			name:      "grpc hostname mismatch",
+			roots:     []*x509.Certificate{leaf},
+			apiConfig: config.APIConfig{Type: config.TypeGRPC, URL: address, GRPC: &config.GRPCConfig{TLS: true}},
+			wantErr:   "for 127.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMonitor(tt.roots...)
+			result := m.perform(tt.apiConfig)
+
+			if result.Status != "down" {
+				t.Fatalf("status = %s (%v), want down", result.Status, result.Err)
+			}
+			if result.TLS == nil || len(result.TLS.Chain) != 1 {
+				t.Fatalf("TLS = %+v, want the refused chain", result.TLS)
+			}
+			if !strings.Contains(result.TLS.VerifyError, tt.wantErr) {
+				t.Errorf("VerifyError = %q, want it to contain %q", result.TLS.VerifyError, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFailedTLSInfo(t *testing.T) {
+	_, leaf := newTestCertificate(t, "other.example")
+	hostnameErr := x509.HostnameError{Certificate: leaf, Host: "127.0.0.1"}
+
+	tests := []struct {
+		name      string
+		err       error
+		wantChain bool
+	}{
+		{"nil", nil, false},
+		{"unrelated", errors.New("connection refused"), false},
+		{"verifyPeerChain", &net.OpError{Op: "remote error", Err: &certVerifyError{err: hostnameErr, chain: []*x509.Certificate{leaf}}}, true},
+		{"crypto/tls", &tls.CertificateVerificationError{UnverifiedCertificates: []*x509.Certificate{leaf}, Err: hostnameErr}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := failedTLSInfo(tt.err)
+			if !tt.wantChain {
+				if info != nil {
+					t.Errorf("info = %+v, want nil", info)
+				}
+				return
+			}
+			if info == nil || len(info.Chain) != 1 {
+				t.Fatalf("info = %+v, want one certificate", info)
+			}
+			if info.VerifyError != hostnameErr.Error() {
+				t.Errorf("VerifyError = %q, want %q", info.VerifyError, hostnameErr.Error())
+			}
+		})
+	}
+}
+
+// TestCertificateAlerts feeds a sequence of checks through certificateAlerts
+// and checks each threshold alerts once until the certificate is renewed.
+func TestCertificateAlerts(t *testing.T) {
+	m := newTestMonitor()
+
+	steps := []struct {
+		daysRemaining int
+		verifyError   string
+		want          []string
+	}{
+		{daysRemaining: 60},
+		{daysRemaining: 30, want: []string{"expires in 30 days"}},
+		{daysRemaining: 29},
+		{daysRemaining: 20},
+		{daysRemaining: 14, want: []string{"expires in 14 days"}},
+		{daysRemaining: 10},
+		{daysRemaining: 2, want: []string{"expires in 2 days"}},
+		{daysRemaining: 1},
+		{daysRemaining: -1},
+		// Renewed.
+		{daysRemaining: 90},
+		{daysRemaining: 12, want: []string{"expires in 12 days"}},
+		{daysRemaining: 12, verifyError: "x509: certificate has expired", want: []string{"verification failed: x509: certificate has expired"}},
+		{daysRemaining: 12, verifyError: "x509: certificate has expired"},
+		{daysRemaining: -3, verifyError: "x509: certificate has expired", want: []string{"expired on"}},
+	}
+
+	var previous *models.TLSInfo
+	for i, step := range steps {
+		current := &models.TLSInfo{
+			DaysRemaining: step.daysRemaining,
+			Chain:         []models.CertificateInfo{{NotAfter: time.Now().AddDate(0, 0, step.daysRemaining)}},
+			VerifyError:   step.verifyError,
+		}
+
+		got := m.certificateAlerts(config.APIConfig{Name: "tls"}, previous, current)
+		if len(got) != len(step.want) {
+			t.Fatalf("step %d (%d days): alerts = %q, want %q", i, step.daysRemaining, got, step.want)
+		}
+		for j := range got {
+			if !strings.Contains(got[j], step.want[j]) {
+				t.Errorf("step %d (%d days): alert = %q, want it to contain %q", i, step.daysRemaining, got[j], step.want[j])
+			}
+		}
+		previous = current
+	}
+}
+
+func TestCertificateAlertsMonitorThresholds(t *testing.T) {
+	m := newTestMonitor()
+	apiConfig := config.APIConfig{Name: "tls", CertExpiryDays: []int{60}}
+	current := &models.TLSInfo{DaysRemaining: 45, Chain: []models.CertificateInfo{{NotAfter: time.Now().AddDate(0, 0, 45)}}}
+
+	got := m.certificateAlerts(apiConfig, nil, current)
+	if len(got) != 1 || current.AlertedThreshold != 60 {
+		t.Errorf("alerts = %q, threshold = %d; want one alert at 60", got, current.AlertedThreshold)
+	}
+}
@@ -0,0 +1,193 @@
+package monitor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+	"railway-api-uptime-monitor/internal/jsonpath"
+	"railway-api-uptime-monitor/internal/models"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+const (
+	// maxContentText caps the normalized content kept for diffing.
+	maxContentText = 32 << 10
+	// maxDiffLines caps the removed and added lines shown in a diff.
+	maxDiffLines = 10
+)
+
+// checkContent hashes the tracked content of a response and alerts when it
+// changed or its keywords stopped (or started) matching. It returns the
+// content to store on the monitor's status.
+func (m *Monitor) checkContent(apiConfig config.APIConfig, previous *models.ContentInfo, body []byte) *models.ContentInfo {
+	contentConfig := *apiConfig.Content
+
+	text, err := normalizeContent(contentConfig, body)
+	if err != nil {
+		info := &models.ContentInfo{Error: err.Error(), CheckedAt: time.Now()}
+		if previous != nil {
+			info.Hash, info.Text, info.KeywordError = previous.Hash, previous.Text, previous.KeywordError
+		}
+		return info
+	}
+
+	sum := sha256.Sum256([]byte(text))
+	info := &models.ContentInfo{
+		Hash:         hex.EncodeToString(sum[:]),
+		Text:         truncate([]byte(text), maxContentText),
+		KeywordError: keywordError(contentConfig, text),
+		CheckedAt:    time.Now(),
+	}
+
+	if previous == nil || previous.Hash == "" {
+		if info.KeywordError != "" {
+			m.sendContentAlert(apiConfig, "keyword", info.KeywordError, "")
+		}
+		return info
+	}
+
+	diff := ""
+	if previous.Hash != info.Hash {
+		diff = contentDiff(previous.Text, info.Text)
+		if contentConfig.TrackChanges {
+			m.sendContentAlert(apiConfig, "content_change", "Content changed", diff)
+		}
+	}
+
+	switch {
+	case info.KeywordError != "" && info.KeywordError != previous.KeywordError:
+		m.sendContentAlert(apiConfig, "keyword", info.KeywordError, diff)
+	case info.KeywordError == "" && previous.KeywordError != "":
+		m.sendContentAlert(apiConfig, "keyword", "Keyword checks pass again", diff)
+	}
+
+	return info
+}
+
+// normalizeContent extracts the tracked part of a body and normalizes its
+// whitespace: runs of spaces collapse and blank lines are dropped, so only
+// meaningful changes alter the hash.
+func normalizeContent(contentConfig config.ContentConfig, body []byte) (string, error) {
+	text := string(body)
+
+	switch {
+	case contentConfig.JSONPath != "":
+		value, found, err := jsonpath.LookupBytes(body, contentConfig.JSONPath)
+		if err != nil {
+			return "", err
+		}
+		if !found {
+			return "", fmt.Errorf("path %s not found", contentConfig.JSONPath)
+		}
+		// Marshaling sorts object keys, so key order does not count as a
+		// change.
+		formatted, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		text = string(formatted)
+
+	case contentConfig.Selector != "":
+		selector, err := cascadia.Compile(contentConfig.Selector)
+		if err != nil {
+			return "", err
+		}
+		doc, err := html.Parse(bytes.NewReader(body))
+		if err != nil {
+			return "", err
+		}
+		nodes := selector.MatchAll(doc)
+		if len(nodes) == 0 {
+			return "", fmt.Errorf("selector %q matched nothing", contentConfig.Selector)
+		}
+		var parts []string
+		for _, node := range nodes {
+			parts = append(parts, nodeText(node))
+		}
+		text = strings.Join(parts, "\n")
+	}
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// nodeText returns the text of an HTML node, one line per text node. Script
+// and style contents are skipped.
+func nodeText(node *html.Node) string {
+	var lines []string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style") {
+			return
+		}
+		if n.Type == html.TextNode {
+			lines = append(lines, n.Data)
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(node)
+
+	return strings.Join(lines, "\n")
+}
+
+// keywordError describes missing required and present forbidden keywords.
+func keywordError(contentConfig config.ContentConfig, text string) string {
+	var problems []string
+	for _, keyword := range contentConfig.Keywords {
+		if !strings.Contains(text, keyword) {
+			problems = append(problems, fmt.Sprintf("missing keyword %q", keyword))
+		}
+	}
+	for _, keyword := range contentConfig.ForbiddenKeywords {
+		if strings.Contains(text, keyword) {
+			problems = append(problems, fmt.Sprintf("forbidden keyword %q found", keyword))
+		}
+	}
+	return strings.Join(problems, "; ")
+}
+
+// contentDiff returns the changed lines between two versions, without the
+// lines they share at the start and end.
+func contentDiff(before, after string) string {
+	a := strings.Split(before, "\n")
+	b := strings.Split(after, "\n")
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var diff []string
+	diff = appendDiffLines(diff, "- ", a[prefix:len(a)-suffix])
+	diff = appendDiffLines(diff, "+ ", b[prefix:len(b)-suffix])
+	return strings.Join(diff, "\n")
+}
+
+func appendDiffLines(diff []string, marker string, lines []string) []string {
+	for i, line := range lines {
+		if i == maxDiffLines {
+			return append(diff, fmt.Sprintf("%s... %d more lines", marker, len(lines)-i))
+		}
+		diff = append(diff, marker+line)
+	}
+	return diff
+}
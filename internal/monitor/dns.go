@@ -0,0 +1,168 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+)
+
+// performDNSCheck resolves the monitor's name and checks that every expected
+// value is part of the answer. The resolution time is the response time.
+func (m *Monitor) performDNSCheck(apiConfig config.APIConfig) checkResult {
+	timeout := m.checkTimeout(apiConfig)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	dnsConfig := config.DNSConfig{RecordType: "A"}
+	if apiConfig.DNS != nil {
+		dnsConfig = *apiConfig.DNS
+	}
+
+	resolver, err := dnsResolver(dnsConfig.Resolver)
+	if err != nil {
+		return checkResult{Status: "down", Err: err}
+	}
+
+	start := time.Now()
+	records, err := lookupRecords(ctx, resolver, dnsConfig.RecordType, apiConfig.URL)
+	responseTime := time.Since(start)
+	if err != nil {
+		result := failedResult(err, responseTime, timeout)
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			result.transient = false
+		}
+		return result
+	}
+
+	result := checkResult{Status: "up", ResponseTime: responseTime, DNSRecords: records}
+	result.Timings.DNSLookup = responseTime
+
+	for _, expected := range dnsConfig.Expected {
+		if !containsRecord(records, expected) {
+			result.Status = "down"
+			result.Err = fmt.Errorf("expected %s record %q not found, got %v", dnsConfig.RecordType, expected, records)
+			break
+		}
+	}
+
+	return result
+}
+
+// dnsResolver returns a resolver that queries address, or the system
+// resolver when address is empty.
+func dnsResolver(address string) (*net.Resolver, error) {
+	if address == "" {
+		return net.DefaultResolver, nil
+	}
+
+	server, err := config.ResolverAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, server)
+		},
+	}, nil
+}
+
+// lookupRecords returns the normalized, sorted answer for name.
+func lookupRecords(ctx context.Context, resolver *net.Resolver, recordType, name string) ([]string, error) {
+	var records []string
+
+	switch recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if recordType == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			records = append(records, ip.String())
+		}
+
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, normalizeHost(cname))
+
+	case "MX":
+		mxs, err := resolver.LookupMX(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range mxs {
+			records = append(records, fmt.Sprintf("%d %s", mx.Pref, normalizeHost(mx.Host)))
+		}
+
+	case "TXT":
+		txts, err := resolver.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, txts...)
+
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", recordType)
+	}
+
+	sort.Strings(records)
+	return records, nil
+}
+
+// containsRecord matches expected against records. Host names are compared
+// case-insensitively without the trailing dot, and an MX record also matches
+// its host alone.
+func containsRecord(records []string, expected string) bool {
+	want := normalizeHost(expected)
+	for _, record := range records {
+		if record == expected || normalizeHost(record) == want {
+			return true
+		}
+		if _, host, ok := strings.Cut(record, " "); ok && host == want {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// dnsChange describes how an answer differs from the previous one. There is
+// no change to report for the first answer.
+func dnsChange(previous, current []string) (string, bool) {
+	if len(previous) == 0 || sameRecords(previous, current) {
+		return "", false
+	}
+	return fmt.Sprintf("DNS records changed from %v to %v", previous, current), true
+}
+
+// sameRecords reports whether two sorted answers are identical.
+func sameRecords(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
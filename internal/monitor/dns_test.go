@@ -0,0 +1,283 @@
+package monitor
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"railway-api-uptime-monitor/internal/config"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// testZone is the data served by startDNSServer, keyed by lowercase
+// fully-qualified name. Names not in the zone get NXDOMAIN.
+type testZone struct {
+	mu      sync.Mutex
+	records map[string][]dnsmessage.ResourceBody
+}
+
+func (z *testZone) set(name string, records ...dnsmessage.ResourceBody) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	z.records[name] = records
+}
+
+// answer returns the records of q's name and type.
+func (z *testZone) answer(q dnsmessage.Question) ([]dnsmessage.ResourceBody, bool) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	records, ok := z.records[strings.ToLower(q.Name.String())]
+	if !ok {
+		return nil, false
+	}
+
+	var answer []dnsmessage.ResourceBody
+	for _, record := range records {
+		if recordType(record) == q.Type {
+			answer = append(answer, record)
+		}
+	}
+	return answer, true
+}
+
+func recordType(body dnsmessage.ResourceBody) dnsmessage.Type {
+	switch body.(type) {
+	case *dnsmessage.AResource:
+		return dnsmessage.TypeA
+	case *dnsmessage.MXResource:
+		return dnsmessage.TypeMX
+	case *dnsmessage.TXTResource:
+		return dnsmessage.TypeTXT
+	}
+	return 0
+}
+
+// startDNSServer serves zone over UDP on a loopback port and returns its
+// address.
+func startDNSServer(t *testing.T, zone *testZone) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if response, err := zone.respond(buf[:n]); err == nil {
+				conn.WriteTo(response, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func (z *testZone) respond(query []byte) ([]byte, error) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(query)
+	if err != nil {
+		return nil, err
+	}
+	q, err := parser.Question()
+	if err != nil {
+		return nil, err
+	}
+
+	answer, found := z.answer(q)
+	responseHeader := dnsmessage.Header{ID: header.ID, Response: true, Authoritative: true}
+	if !found {
+		responseHeader.RCode = dnsmessage.RCodeNameError
+	}
+
+	builder := dnsmessage.NewBuilder(nil, responseHeader)
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := builder.Question(q); err != nil {
+		return nil, err
+	}
+	if err := builder.StartAnswers(); err != nil {
+		return nil, err
+	}
+
+	rh := dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: 60}
+	for _, record := range answer {
+		switch record := record.(type) {
+		case *dnsmessage.AResource:
+			err = builder.AResource(rh, *record)
+		case *dnsmessage.MXResource:
+			err = builder.MXResource(rh, *record)
+		case *dnsmessage.TXTResource:
+			err = builder.TXTResource(rh, *record)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return builder.Finish()
+}
+
+func newTestZone() *testZone {
+	zone := &testZone{records: map[string][]dnsmessage.ResourceBody{}}
+	zone.set("example.test.",
+		&dnsmessage.AResource{A: [4]byte{192, 0, 2, 2}},
+		&dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}},
+		&dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("Mail.Example.Test.")},
+		&dnsmessage.MXResource{Pref: 20, MX: dnsmessage.MustNewName("backup.example.test.")},
+		&dnsmessage.TXTResource{TXT: []string{"v=spf1 -all"}},
+	)
+	return zone
+}
+
+func dnsConfig(resolver, name, recordType string, expected ...string) config.APIConfig {
+	return config.APIConfig{
+		Name: "dns",
+		Type: config.TypeDNS,
+		URL:  name,
+		DNS:  &config.DNSConfig{RecordType: recordType, Resolver: resolver, Expected: expected},
+	}
+}
+
+func TestDNSCheck(t *testing.T) {
+	resolver := startDNSServer(t, newTestZone())
+
+	tests := []struct {
+		name        string
+		apiConfig   config.APIConfig
+		wantStatus  string
+		wantRecords []string
+	}{
+		{
+			name:        "A",
+			apiConfig:   dnsConfig(resolver, "example.test", "A", "192.0.2.1"),
+			wantStatus:  "up",
+			wantRecords: []string{"192.0.2.1", "192.0.2.2"},
+		},
+		{
+			name:        "MX by host",
+			apiConfig:   dnsConfig(resolver, "example.test", "MX", "MAIL.example.test."),
+			wantStatus:  "up",
+			wantRecords: []string{"10 mail.example.test", "20 backup.example.test"},
+		},
+		{
+			name:        "MX with preference",
+			apiConfig:   dnsConfig(resolver, "example.test", "MX", "20 backup.example.test"),
+			wantStatus:  "up",
+			wantRecords: []string{"10 mail.example.test", "20 backup.example.test"},
+		},
+		{
+			name:        "TXT",
+			apiConfig:   dnsConfig(resolver, "example.test", "TXT", "v=spf1 -all"),
+			wantStatus:  "up",
+			wantRecords: []string{"v=spf1 -all"},
+		},
+		{
+			name:        "expected record missing",
+			apiConfig:   dnsConfig(resolver, "example.test", "A", "192.0.2.1", "192.0.2.3"),
+			wantStatus:  "down",
+			wantRecords: []string{"192.0.2.1", "192.0.2.2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMonitor()
+			result := m.perform(tt.apiConfig)
+
+			if result.Status != tt.wantStatus {
+				t.Fatalf("status = %s (%v), want %s", result.Status, result.Err, tt.wantStatus)
+			}
+			if !sameRecords(result.DNSRecords, tt.wantRecords) {
+				t.Errorf("records = %q, want %q", result.DNSRecords, tt.wantRecords)
+			}
+		})
+	}
+}
+
+func TestDNSCheckNXDOMAIN(t *testing.T) {
+	resolver := startDNSServer(t, newTestZone())
+
+	m := newTestMonitor()
+	result := m.perform(dnsConfig(resolver, "missing.example.test", "A"))
+
+	if result.Status != "down" {
+		t.Fatalf("status = %s (%v), want down", result.Status, result.Err)
+	}
+	var dnsErr *net.DNSError
+	if !errors.As(result.Err, &dnsErr) || !dnsErr.IsNotFound {
+		t.Errorf("err = %v, want a not-found DNS error", result.Err)
+	}
+	if result.transient {
+		t.Error("NXDOMAIN is transient, want it reported without retries")
+	}
+}
+
+func TestDNSChange(t *testing.T) {
+	zone := newTestZone()
+	resolver := startDNSServer(t, zone)
+	apiConfig := dnsConfig(resolver, "example.test", "A")
+
+	m := newTestMonitor()
+	first := m.perform(apiConfig)
+	if _, changed := dnsChange(nil, first.DNSRecords); changed {
+		t.Error("first answer reported as a change")
+	}
+
+	// Same records in a different order.
+	zone.set("example.test.",
+		&dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}},
+		&dnsmessage.AResource{A: [4]byte{192, 0, 2, 2}},
+	)
+	second := m.perform(apiConfig)
+	if message, changed := dnsChange(first.DNSRecords, second.DNSRecords); changed {
+		t.Errorf("reordered answer reported as a change: %s", message)
+	}
+
+	zone.set("example.test.", &dnsmessage.AResource{A: [4]byte{198, 51, 100, 7}})
+	third := m.perform(apiConfig)
+	message, changed := dnsChange(second.DNSRecords, third.DNSRecords)
+	if !changed {
+		t.Fatal("changed answer not reported")
+	}
+	if want := "DNS records changed from [192.0.2.1 192.0.2.2] to [198.51.100.7]"; message != want {
+		t.Errorf("message = %q, want %q", message, want)
+	}
+}
+
+func TestContainsRecord(t *testing.T) {
+	tests := []struct {
+		name     string
+		records  []string
+		expected string
+		want     bool
+	}{
+		{"exact", []string{"192.0.2.1"}, "192.0.2.1", true},
+		{"absent", []string{"192.0.2.1"}, "192.0.2.10", false},
+		{"empty answer", nil, "192.0.2.1", false},
+		{"host case and trailing dot", []string{"target.example.test"}, "Target.Example.Test.", true},
+		{"MX host alone", []string{"10 mail.example.test"}, "mail.example.test", true},
+		{"MX host with preference", []string{"10 mail.example.test"}, "10 MAIL.example.test.", true},
+		{"MX wrong preference", []string{"10 mail.example.test"}, "20 mail.example.test", false},
+		{"TXT", []string{"v=spf1 -all"}, "v=spf1 -all", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containsRecord(tt.records, tt.expected); got != tt.want {
+				t.Errorf("containsRecord(%q, %q) = %v, want %v", tt.records, tt.expected, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// performGRPCCheck calls grpc.health.v1.Health/Check. SERVING maps to up;
+// NOT_SERVING, UNKNOWN and SERVICE_UNKNOWN map to down.
+func (m *Monitor) performGRPCCheck(apiConfig config.APIConfig) checkResult {
+	timeout := m.checkTimeout(apiConfig)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	address, err := config.HostPort(apiConfig.URL)
+	if err != nil {
+		return checkResult{Status: "down", Err: err}
+	}
+
+	grpcConfig := config.GRPCConfig{}
+	if apiConfig.GRPC != nil {
+		grpcConfig = *apiConfig.GRPC
+	}
+
+	// The chain is recorded from inside the handshake because gRPC only
+	// reports verification failures as text.
+	var mu sync.Mutex
+	var peerCerts []*x509.Certificate
+	var verifyErr error
+
+	creds := insecure.NewCredentials()
+	if grpcConfig.TLS {
+		host, _, _ := net.SplitHostPort(address)
+		verify := m.verifyPeerChain(host)
+		creds = credentials.NewTLS(&tls.Config{
+			InsecureSkipVerify: true,
+			VerifyConnection: func(cs tls.ConnectionState) error {
+				err := verify(cs)
+
+				mu.Lock()
+				defer mu.Unlock()
+				peerCerts, verifyErr = cs.PeerCertificates, err
+				return err
+			},
+		})
+	}
+
+	conn, err := grpc.DialContext(ctx, address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUserAgent(userAgent),
+	)
+	if err != nil {
+		return checkResult{Status: "down", Err: err}
+	}
+	defer conn.Close()
+
+	if len(grpcConfig.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(grpcConfig.Metadata))
+	}
+
+	start := time.Now()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: grpcConfig.Service,
+	})
+	responseTime := time.Since(start)
+
+	mu.Lock()
+	tls := tlsInfo(peerCerts, verifyErr)
+	mu.Unlock()
+
+	if err != nil {
+		result := grpcFailedResult(err, responseTime, timeout)
+		result.TLS = tls
+		return result
+	}
+
+	result := checkResult{Status: "up", ResponseTime: responseTime, TLS: tls}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		result.Status = "down"
+		result.Err = fmt.Errorf("health status %s", resp.Status)
+	}
+
+	return result
+}
+
+// grpcFailedResult classifies a failed health RPC by its status code.
+func grpcFailedResult(err error, responseTime, timeout time.Duration) checkResult {
+	st := status.Convert(err)
+
+	switch st.Code() {
+	case codes.DeadlineExceeded:
+		return checkResult{
+			Status:       "timeout",
+			ResponseTime: responseTime,
+			Err:          fmt.Errorf("timed out after %s: %v", timeout, err),
+			transient:    true,
+		}
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return checkResult{Status: "down", ResponseTime: responseTime, Err: err, transient: true}
+	case codes.NotFound:
+		return checkResult{Status: "down", ResponseTime: responseTime, Err: fmt.Errorf("health status SERVICE_UNKNOWN: %s", st.Message())}
+	case codes.Unimplemented:
+		return checkResult{Status: "down", ResponseTime: responseTime, Err: fmt.Errorf("server does not implement grpc.health.v1.Health: %s", st.Message())}
+	default:
+		return checkResult{Status: "down", ResponseTime: responseTime, Err: err}
+	}
+}
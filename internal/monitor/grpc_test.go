@@ -0,0 +1,132 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"railway-api-uptime-monitor/internal/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// startHealthServer serves grpc.health.v1.Health on a loopback port. The
+// metadata of each request is recorded on the returned recorder.
+func startHealthServer(t *testing.T) (string, *health.Server, *metadataRecorder) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := &metadataRecorder{}
+	server := grpc.NewServer(grpc.UnaryInterceptor(recorder.intercept))
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String(), healthServer, recorder
+}
+
+type metadataRecorder struct {
+	mu   sync.Mutex
+	last metadata.MD
+}
+
+func (r *metadataRecorder) intercept(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	r.mu.Lock()
+	r.last = md
+	r.mu.Unlock()
+
+	return handler(ctx, req)
+}
+
+func (r *metadataRecorder) get(key string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last.Get(key)
+}
+
+func TestGRPCCheck(t *testing.T) {
+	address, healthServer, _ := startHealthServer(t)
+	healthServer.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("billing", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	tests := []struct {
+		name       string
+		service    string
+		wantStatus string
+		wantErr    string
+	}{
+		{name: "server", service: "", wantStatus: "up"},
+		{name: "serving", service: "orders", wantStatus: "up"},
+		{name: "not serving", service: "billing", wantStatus: "down", wantErr: "health status NOT_SERVING"},
+		{name: "unknown service", service: "shipping", wantStatus: "down", wantErr: "health status SERVICE_UNKNOWN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMonitor()
+			result := m.perform(config.APIConfig{
+				Name: "grpc",
+				Type: config.TypeGRPC,
+				URL:  address,
+				GRPC: &config.GRPCConfig{Service: tt.service},
+			})
+
+			if result.Status != tt.wantStatus {
+				t.Fatalf("status = %s (%v), want %s", result.Status, result.Err, tt.wantStatus)
+			}
+			if tt.wantErr == "" {
+				if result.Err != nil {
+					t.Errorf("err = %v, want none", result.Err)
+				}
+				return
+			}
+			if result.Err == nil || !strings.Contains(result.Err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", result.Err, tt.wantErr)
+			}
+			if result.transient {
+				t.Error("result is transient, want it reported without retries")
+			}
+		})
+	}
+}
+
+func TestGRPCCheckSendsMetadata(t *testing.T) {
+	address, _, recorder := startHealthServer(t)
+
+	m := newTestMonitor()
+	result := m.perform(config.APIConfig{
+		Name: "grpc",
+		Type: config.TypeGRPC,
+		URL:  address,
+		GRPC: &config.GRPCConfig{Metadata: map[string]string{
+			"Authorization": "Bearer token",
+			"x-tenant":      "acme",
+		}},
+	})
+
+	if result.Status != "up" {
+		t.Fatalf("status = %s (%v), want up", result.Status, result.Err)
+	}
+	if got := recorder.get("authorization"); len(got) != 1 || got[0] != "Bearer token" {
+		t.Errorf("authorization = %q, want [Bearer token]", got)
+	}
+	if got := recorder.get("x-tenant"); len(got) != 1 || got[0] != "acme" {
+		t.Errorf("x-tenant = %q, want [acme]", got)
+	}
+	if got := recorder.get("user-agent"); len(got) != 1 || !strings.HasPrefix(got[0], userAgent) {
+		t.Errorf("user-agent = %q, want it to start with %q", got, userAgent)
+	}
+}
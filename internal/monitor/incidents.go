@@ -0,0 +1,136 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+	"railway-api-uptime-monitor/internal/database"
+	"railway-api-uptime-monitor/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// recordIncidentCheck adds a failed check to the monitor's active incident,
+// opening one on the first failure. It returns the incident, or nil if it
+// could not be stored.
+func (m *Monitor) recordIncidentCheck(apiConfig config.APIConfig, result checkResult) *models.Incident {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	event := models.IncidentEvent{
+		Type:       "check",
+		Timestamp:  time.Now(),
+		Message:    fmt.Sprintf("Check %s", result.Status),
+		Status:     result.Status,
+		StatusCode: result.StatusCode,
+	}
+	if result.Err != nil {
+		event.Message = result.Err.Error()
+	}
+	if !result.checkID.IsZero() {
+		event.CheckID = &result.checkID
+	}
+
+	incident, err := m.db.RecordIncidentCheck(ctx, apiConfig.Name, event)
+	if err != nil {
+		log.Printf("Error recording incident for %s: %v", apiConfig.Name, err)
+		return nil
+	}
+	return incident
+}
+
+// noteIncidentNotification adds a notification sent for an incident to its
+// timeline. An empty notification means none was sent.
+func (m *Monitor) noteIncidentNotification(incident *models.Incident, notification string) {
+	if incident == nil || notification == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := m.db.AddIncidentEvent(ctx, incident.ID, models.IncidentEvent{
+		Type:      "notification",
+		Timestamp: time.Now(),
+		Message:   notification,
+	})
+	if err != nil {
+		log.Printf("Error updating incident: %v", err)
+	}
+}
+
+// closeOutage resolves the monitor's outage alert and active incident once it
+// recovers.
+func (m *Monitor) closeOutage(apiConfig config.APIConfig) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	incident, err := m.db.ActiveIncident(ctx, apiConfig.Name)
+	if err != nil {
+		log.Printf("Error loading incident for %s: %v", apiConfig.Name, err)
+	}
+	if incident != nil {
+		resolved, err := m.db.ResolveIncident(ctx, incident.ID, "", "API is back online")
+		if err != nil {
+			log.Printf("Error resolving incident: %v", err)
+		} else {
+			incident = resolved
+		}
+	}
+
+	notification := m.resolveAlert(apiConfig, outageAlertTypes, "up", "API is back online", incident)
+	m.noteIncidentNotification(incident, notification)
+}
+
+// ResolveIncident resolves an incident by hand, along with the monitor's open
+// outage alert, and sends an "incident_resolved" notification rather than
+// "up", since the monitor may still be failing. If it is, its next failed
+// check opens a new incident.
+func (m *Monitor) ResolveIncident(ctx context.Context, id primitive.ObjectID, author, message string) (*models.Incident, error) {
+	incident, err := m.db.ResolveIncident(ctx, id, author, message)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvedBy := "Incident resolved manually"
+	if author != "" {
+		resolvedBy = fmt.Sprintf("Incident resolved by %s", author)
+	}
+	// The monitor's notify rules pick the channels; a deleted monitor
+	// notifies every channel.
+	apiConfig := config.APIConfig{Name: incident.APIName}
+	if stored, err := m.db.GetMonitor(ctx, incident.APIName); err == nil {
+		apiConfig = *stored
+	}
+
+	notification := m.resolveAlert(apiConfig, outageAlertTypes, "incident_resolved", resolvedBy, incident)
+	m.noteIncidentNotification(incident, notification)
+
+	return incident, nil
+}
+
+// CloseDeleted resolves the active incident and open alerts of a deleted
+// monitor without notifying, so they do not outlive it. It waits for a check
+// of the monitor that is in flight, which could otherwise reopen them.
+func (m *Monitor) CloseDeleted(ctx context.Context, apiName string) error {
+	m.waitCheck(apiName)
+	defer m.finishCheck(apiName)
+
+	incident, err := m.db.ActiveIncident(ctx, apiName)
+	if err != nil {
+		return err
+	}
+	if incident != nil {
+		_, err := m.db.ResolveIncident(ctx, incident.ID, "", "Monitor deleted")
+		if err != nil && !errors.Is(err, database.ErrIncidentResolved) {
+			return err
+		}
+	}
+
+	_, err = m.db.ResolveOpenAlerts(ctx, apiName)
+	return err
+}
@@ -1,11 +1,16 @@
 package monitor
 
 import (
-	"bytes"
 	"context"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
 	"time"
 
 	"railway-api-uptime-monitor/internal/config"
@@ -14,6 +19,7 @@ import (
 	"railway-api-uptime-monitor/internal/webhook"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Monitor struct {
@@ -21,94 +27,364 @@ type Monitor struct {
 	notifier *webhook.Notifier
 	config   *config.Config
 	client   *http.Client
+
+	// rootCAs verifies peer certificates; nil means the system roots.
+	rootCAs *x509.CertPool
+
+	// workers bounds how many checks run at once.
+	workers chan struct{}
+
+	mu       sync.Mutex
+	inFlight map[string]bool
+	// idle is signalled whenever a check finishes.
+	idle *sync.Cond
+}
+
+// CheckSummary reports the outcome of dispatching one batch of checks.
+type CheckSummary struct {
+	Ran          int
+	Skipped      int
+	StillRunning int
 }
 
 func New(db *database.Database, notifier *webhook.Notifier, cfg *config.Config) *Monitor {
-	return &Monitor{
+	maxConcurrent := cfg.MaxConcurrent
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+
+	m := &Monitor{
 		db:       db,
 		notifier: notifier,
 		config:   cfg,
-		client: &http.Client{
-			Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second,
-		},
+		workers:  make(chan struct{}, maxConcurrent),
+		inFlight: make(map[string]bool),
 	}
+	m.idle = sync.NewCond(&m.mu)
+	// Timeouts are applied per check through the request context.
+	m.client = m.newHTTPClient()
+
+	return m
 }
 
-func (m *Monitor) CheckAllAPIs() {
-	apisConfig, err := config.LoadAPIs()
-	if err != nil {
-		log.Printf("Error loading API config: %v", err)
-		return
+// RunChecks checks the given monitors on the bounded worker pool and waits
+// for them to finish. A monitor whose previous check is still in flight is
+// not checked again; monitors still waiting for a worker when ctx is done
+// are skipped.
+func (m *Monitor) RunChecks(ctx context.Context, apis []config.APIConfig) CheckSummary {
+	var summary CheckSummary
+	var wg sync.WaitGroup
+
+	for _, apiConfig := range apis {
+		if !m.startCheck(apiConfig.Name) {
+			summary.StillRunning++
+			continue
+		}
+
+		select {
+		case m.workers <- struct{}{}:
+		case <-ctx.Done():
+			m.finishCheck(apiConfig.Name)
+			summary.Skipped++
+			continue
+		}
+
+		summary.Ran++
+		wg.Add(1)
+		go func(apiConfig config.APIConfig) {
+			defer wg.Done()
+			defer func() { <-m.workers }()
+			defer m.finishCheck(apiConfig.Name)
+
+			m.checkAPI(apiConfig)
+		}(apiConfig)
 	}
 
-	for _, apiConfig := range apisConfig.APIs {
-		go m.checkAPI(apiConfig)
+	wg.Wait()
+	return summary
+}
+
+// startCheck marks a monitor as in flight, returning false if it already is.
+func (m *Monitor) startCheck(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.inFlight[name] {
+		return false
+	}
+	m.inFlight[name] = true
+	return true
+}
+
+// waitCheck waits until the monitor has no check in flight and marks it as
+// in flight, for results that must not be dropped like startCheck would.
+func (m *Monitor) waitCheck(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for m.inFlight[name] {
+		m.idle.Wait()
 	}
+	m.inFlight[name] = true
+}
+
+func (m *Monitor) finishCheck(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.inFlight, name)
+	m.idle.Broadcast()
+}
+
+// checkResult is the outcome of a check, or of a single attempt while
+// retrying one.
+type checkResult struct {
+	Status        string
+	StatusCode    int
+	StatusRule    string
+	ResponseTime  time.Duration
+	Timings       phaseTimings
+	TLS           *models.TLSInfo
+	DNSRecords    []string
+	Ping          *models.PingStats
+	Log           string
+	Heartbeat     bool
+	Severity      string
+	Steps         []models.StepResult
+	Err           error
+	Attempts      int
+	AttemptErrors []string
+
+	// header and body of an HTTP response, kept for the extractions of
+	// multi-step checks.
+	header http.Header
+	body   []byte
+
+	// checkID is the ID of the stored health check.
+	checkID primitive.ObjectID
+
+	// transient marks failures worth retrying, such as connection errors,
+	// timeouts and 5xx responses.
+	transient bool
 }
 
 func (m *Monitor) checkAPI(apiConfig config.APIConfig) {
-	start := time.Now()
+	if apiConfig.Type == config.TypePush {
+		if result, missed := m.missedHeartbeat(apiConfig); missed {
+			m.recordResult(apiConfig, result)
+		}
+		return
+	}
 
-	status, statusCode, err := m.performHealthCheck(apiConfig)
-	responseTime := time.Since(start)
+	m.recordResult(apiConfig, m.resolveAndCheck(apiConfig))
+}
 
+// recordResult stores a check result as a health check and updates the
+// monitor's status.
+func (m *Monitor) recordResult(apiConfig config.APIConfig, result checkResult) {
 	healthCheck := models.HealthCheck{
-		APIName:      apiConfig.Name,
-		URL:          apiConfig.URL,
-		Status:       status,
-		StatusCode:   statusCode,
-		ResponseTime: responseTime,
-		Timestamp:    time.Now(),
+		APIName:       apiConfig.Name,
+		URL:           apiConfig.URL,
+		Status:        result.Status,
+		StatusCode:    result.StatusCode,
+		StatusRule:    result.StatusRule,
+		ResponseTime:  result.ResponseTime,
+		Timestamp:     time.Now(),
+		Attempts:      result.Attempts,
+		AttemptErrors: result.AttemptErrors,
+		DNSRecords:    result.DNSRecords,
+		Ping:          result.Ping,
+		Log:           result.Log,
+		Steps:         result.Steps,
+
+		DNSLookup:       result.Timings.DNSLookup,
+		TCPConnect:      result.Timings.TCPConnect,
+		TLSHandshake:    result.Timings.TLSHandshake,
+		TimeToFirstByte: result.Timings.TimeToFirstByte,
+		ContentTransfer: result.Timings.ContentTransfer,
+		Handshake:       result.Timings.Handshake,
+		RoundTrip:       result.Timings.RoundTrip,
 	}
 
-	if err != nil {
-		healthCheck.ErrorMessage = err.Error()
+	if result.Err != nil {
+		healthCheck.ErrorMessage = result.Err.Error()
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	collection := m.db.GetCollection("health_checks")
-	_, insertErr := collection.InsertOne(ctx, healthCheck)
+	insertResult, insertErr := collection.InsertOne(ctx, healthCheck)
 	if insertErr != nil {
 		log.Printf("Error inserting health check: %v", insertErr)
+	} else {
+		result.checkID = insertResult.InsertedID.(primitive.ObjectID)
 	}
 
-	m.updateAPIStatus(apiConfig, status, statusCode, responseTime, err)
+	m.updateAPIStatus(apiConfig, result)
 
-	log.Printf("Checked %s: %s (%d) - %v", apiConfig.Name, status, statusCode, responseTime)
+	log.Printf("Checked %s: %s (%d) - %v after %d attempt(s)", apiConfig.Name, result.Status, result.StatusCode, result.ResponseTime, result.Attempts)
 }
 
-func (m *Monitor) performHealthCheck(apiConfig config.APIConfig) (string, int, error) {
-	var req *http.Request
-	var err error
+// resolveAndCheck resolves the monitor's secret placeholders and runs the
+// check, retrying transient failures. Secret values are scrubbed from the
+// returned errors, which end up in the database and API responses.
+func (m *Monitor) resolveAndCheck(apiConfig config.APIConfig) checkResult {
+	resolved, secrets, err := apiConfig.ResolveSecrets()
+	if err != nil {
+		return checkResult{Status: "down", Err: err}
+	}
 
-	if apiConfig.Method == "POST" {
-		req, err = http.NewRequest("POST", apiConfig.URL, bytes.NewBuffer([]byte{}))
-	} else {
-		req, err = http.NewRequest("GET", apiConfig.URL, nil)
+	result := m.checkWithRetries(resolved)
+	applySLO(apiConfig, &result)
+	if result.Err != nil {
+		result.Err = errors.New(secrets.Redact(result.Err.Error()))
+	}
+	for i, attemptErr := range result.AttemptErrors {
+		result.AttemptErrors[i] = secrets.Redact(attemptErr)
+	}
+	for i := range result.Steps {
+		result.Steps[i].ErrorMessage = secrets.Redact(result.Steps[i].ErrorMessage)
 	}
 
-	if err != nil {
-		return "down", 0, err
+	return result
+}
+
+// perform runs a single check attempt using the monitor's type.
+func (m *Monitor) perform(apiConfig config.APIConfig) checkResult {
+	switch apiConfig.Type {
+	case config.TypeTCP:
+		return m.performTCPCheck(apiConfig)
+	case config.TypeDNS:
+		return m.performDNSCheck(apiConfig)
+	case config.TypePing:
+		return m.performPingCheck(apiConfig)
+	case config.TypeGRPC:
+		return m.performGRPCCheck(apiConfig)
+	case config.TypeWebSocket:
+		return m.performWebSocketCheck(apiConfig)
+	case config.TypeSteps:
+		return m.performStepsCheck(apiConfig)
+	default:
+		return m.performHealthCheck(apiConfig)
 	}
+}
+
+func (m *Monitor) performHealthCheck(apiConfig config.APIConfig) checkResult {
+	timeout := m.checkTimeout(apiConfig)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-	req.Header.Set("User-Agent", "Railway-API-Uptime-Monitor/1.0")
+	trace := &timingTrace{}
+	ctx = httptrace.WithClientTrace(ctx, trace.clientTrace())
 
+	req, err := buildRequest(ctx, apiConfig)
+	if err != nil {
+		return checkResult{Status: "down", Err: err}
+	}
+
+	start := time.Now()
 	resp, err := m.client.Do(req)
 	if err != nil {
-		return "down", 0, err
+		result := failedResult(err, time.Since(start), timeout)
+		result.Timings = trace.timings(time.Now())
+		result.TLS = failedTLSInfo(err)
+		return result
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == apiConfig.ExpectedStatus {
-		return "up", resp.StatusCode, nil
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	end := time.Now()
+	responseTime := end.Sub(start)
+	if err != nil {
+		result := failedResult(fmt.Errorf("error reading response body: %w", err), responseTime, timeout)
+		result.StatusCode = resp.StatusCode
+		result.Timings = trace.timings(end)
+		result.transient = true
+		return result
 	}
 
-	return "down", resp.StatusCode, fmt.Errorf("unexpected status code: %d, expected: %d", resp.StatusCode, apiConfig.ExpectedStatus)
+	result := checkResult{
+		Status:       "up",
+		StatusCode:   resp.StatusCode,
+		ResponseTime: responseTime,
+		Timings:      trace.timings(end),
+		header:       resp.Header,
+		body:         body,
+	}
+	if resp.TLS != nil {
+		result.TLS = tlsInfo(resp.TLS.PeerCertificates, nil)
+	}
+
+	rule, err := matchStatus(apiConfig, resp.StatusCode)
+	if err != nil {
+		result.Status = "down"
+		result.Err = err
+		result.transient = resp.StatusCode >= 500
+		return result
+	}
+	result.StatusRule = rule
+
+	if err := evaluateAssertions(apiConfig.Assertions, resp.Header, body); err != nil {
+		result.Status = "down"
+		result.Err = err
+	}
+
+	return result
+}
+
+// matchStatus returns the expected status rule that code satisfies, or an
+// error listing the rules it failed.
+func matchStatus(apiConfig config.APIConfig, code int) (string, error) {
+	ranges, err := apiConfig.ExpectedStatusRanges()
+	if err != nil {
+		return "", err
+	}
+
+	rules := make([]string, len(ranges))
+	for i, r := range ranges {
+		if r.Contains(code) {
+			return r.String(), nil
+		}
+		rules[i] = r.String()
+	}
+
+	if len(rules) == 1 {
+		return "", fmt.Errorf("unexpected status code: %d, expected: %s", code, rules[0])
+	}
+	return "", fmt.Errorf("unexpected status code: %d, expected one of: %s", code, strings.Join(rules, ", "))
+}
+
+// checkTimeout returns the monitor's own timeout, or TIMEOUT_SECONDS when it
+// does not set one.
+func (m *Monitor) checkTimeout(apiConfig config.APIConfig) time.Duration {
+	if apiConfig.Timeout > 0 {
+		return time.Duration(apiConfig.Timeout) * time.Second
+	}
+	return time.Duration(m.config.TimeoutSeconds) * time.Second
 }
 
-func (m *Monitor) updateAPIStatus(apiConfig config.APIConfig, status string, statusCode int, responseTime time.Duration, err error) {
+// failedResult builds the result for a check that got no usable response,
+// classifying timeouts separately from other failures.
+func failedResult(err error, responseTime, timeout time.Duration) checkResult {
+	if isTimeout(err) {
+		return checkResult{
+			Status:       "timeout",
+			ResponseTime: responseTime,
+			Err:          fmt.Errorf("timed out after %s: %v", timeout, err),
+			transient:    true,
+		}
+	}
+
+	return checkResult{Status: "down", ResponseTime: responseTime, Err: err, transient: isTransient(err)}
+}
+
+// isFailure reports whether a check status counts against the monitor.
+func isFailure(status string) bool {
+	return status == "down" || status == "timeout"
+}
+
+func (m *Monitor) updateAPIStatus(apiConfig config.APIConfig, result checkResult) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -123,26 +399,48 @@ func (m *Monitor) updateAPIStatus(apiConfig config.APIConfig, status string, sta
 	if findErr != nil {
 		newStatus := models.APIStatus{
 			Name:          apiConfig.Name,
+			Type:          apiConfig.Type,
 			URL:           apiConfig.URL,
 			Method:        apiConfig.Method,
-			Status:        status,
-			StatusCode:    statusCode,
-			ResponseTime:  responseTime,
+			Status:        result.Status,
+			StatusCode:    result.StatusCode,
+			ResponseTime:  result.ResponseTime,
 			LastChecked:   now,
 			DowntimeCount: 0,
 			UptimePercent: 100.0,
+			TLS:           result.TLS,
+			DNSRecords:    result.DNSRecords,
+			Ping:          result.Ping,
 		}
+		if result.Heartbeat {
+			newStatus.LastHeartbeat = now
+		}
+		newStatus.FailedStep = failedStep(result.Steps)
+		if apiConfig.Content != nil && !isFailure(result.Status) {
+			newStatus.Content = m.checkContent(apiConfig, nil, result.body)
+		}
+		m.checkCertificate(apiConfig, nil, result.TLS)
 
-		if status == "up" {
+		if !isFailure(result.Status) {
 			newStatus.LastUp = now
+			newStatus.Severity = result.Severity
+			if result.Status == "degraded" {
+				newStatus.DegradedPercent = 100.0
+			}
+			m.checkDegraded(apiConfig, models.APIStatus{}, result)
 		} else {
 			newStatus.LastDown = now
 			newStatus.DowntimeCount = 1
 			newStatus.UptimePercent = 0.0
+			incident := m.recordIncidentCheck(apiConfig, result)
+			if alert, ok := m.outageAlert(apiConfig, result, newStatus.DowntimeCount); ok {
+				notification := m.openAlert(apiConfig, outageAlertTypes, alert, incident)
+				m.noteIncidentNotification(incident, notification)
+			}
 		}
 
-		if err != nil {
-			newStatus.ErrorMessage = err.Error()
+		if result.Err != nil {
+			newStatus.ErrorMessage = result.Err.Error()
 		}
 
 		_, insertErr := collection.InsertOne(ctx, newStatus)
@@ -154,38 +452,76 @@ func (m *Monitor) updateAPIStatus(apiConfig config.APIConfig, status string, sta
 
 	update := bson.M{
 		"$set": bson.M{
-			"status":        status,
-			"status_code":   statusCode,
-			"response_time": responseTime,
+			"type":          apiConfig.Type,
+			"url":           apiConfig.URL,
+			"method":        apiConfig.Method,
+			"status":        result.Status,
+			"status_code":   result.StatusCode,
+			"response_time": result.ResponseTime,
 			"last_checked":  now,
 		},
 	}
 
-	if status == "up" {
+	if !isFailure(result.Status) {
 		update["$set"].(bson.M)["last_up"] = now
 		update["$set"].(bson.M)["error_message"] = ""
+		update["$set"].(bson.M)["severity"] = result.Severity
+		if result.Err != nil {
+			update["$set"].(bson.M)["error_message"] = result.Err.Error()
+		}
 
-		if existingStatus.Status == "down" {
+		if isFailure(existingStatus.Status) {
 			update["$set"].(bson.M)["downtime_count"] = 0
-			m.sendAlert(apiConfig.Name, "up", "API is back online")
+			m.closeOutage(apiConfig)
 		}
+		m.checkDegraded(apiConfig, existingStatus, result)
 	} else {
 		update["$set"].(bson.M)["last_down"] = now
-		if err != nil {
-			update["$set"].(bson.M)["error_message"] = err.Error()
+		if result.Err != nil {
+			update["$set"].(bson.M)["error_message"] = result.Err.Error()
 		}
 
 		newDowntimeCount := existingStatus.DowntimeCount + 1
 		update["$set"].(bson.M)["downtime_count"] = newDowntimeCount
 
-		if newDowntimeCount >= m.config.DowntimeThreshold {
-			message := fmt.Sprintf("API has been down for %d consecutive checks", newDowntimeCount)
-			m.sendAlert(apiConfig.Name, "down", message)
+		incident := m.recordIncidentCheck(apiConfig, result)
+		if alert, ok := m.outageAlert(apiConfig, result, newDowntimeCount); ok {
+			notification := m.openAlert(apiConfig, outageAlertTypes, alert, incident)
+			m.noteIncidentNotification(incident, notification)
 		}
 	}
 
-	uptimePercent := m.calculateUptimePercent(apiConfig.Name)
+	if result.TLS != nil {
+		m.checkCertificate(apiConfig, existingStatus.TLS, result.TLS)
+		update["$set"].(bson.M)["tls"] = result.TLS
+	}
+
+	if result.Ping != nil {
+		update["$set"].(bson.M)["ping"] = result.Ping
+	}
+
+	if result.Heartbeat {
+		update["$set"].(bson.M)["last_heartbeat"] = now
+	}
+
+	if apiConfig.Content != nil && !isFailure(result.Status) {
+		update["$set"].(bson.M)["content"] = m.checkContent(apiConfig, existingStatus.Content, result.body)
+	}
+
+	if apiConfig.Type == config.TypeSteps {
+		update["$set"].(bson.M)["failed_step"] = failedStep(result.Steps)
+	}
+
+	if result.DNSRecords != nil {
+		if message, changed := dnsChange(existingStatus.DNSRecords, result.DNSRecords); changed {
+			m.sendAlert(apiConfig, "dns_change", message)
+		}
+		update["$set"].(bson.M)["dns_records"] = result.DNSRecords
+	}
+
+	uptimePercent, degradedPercent := m.calculateUptimePercent(apiConfig.Name)
 	update["$set"].(bson.M)["uptime_percent"] = uptimePercent
+	update["$set"].(bson.M)["degraded_percent"] = degradedPercent
 
 	_, updateErr := collection.UpdateOne(ctx, filter, update)
 	if updateErr != nil {
@@ -193,7 +529,9 @@ func (m *Monitor) updateAPIStatus(apiConfig config.APIConfig, status string, sta
 	}
 }
 
-func (m *Monitor) calculateUptimePercent(apiName string) float64 {
+// calculateUptimePercent returns the share of the last 24 hours of checks
+// that were up or degraded, and the share that were degraded.
+func (m *Monitor) calculateUptimePercent(apiName string) (float64, float64) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -207,40 +545,105 @@ func (m *Monitor) calculateUptimePercent(apiName string) float64 {
 
 	total, err := collection.CountDocuments(ctx, filter)
 	if err != nil || total == 0 {
-		return 100.0
+		return 100.0, 0.0
 	}
 
 	upFilter := bson.M{
 		"api_name":  apiName,
 		"timestamp": bson.M{"$gte": since},
-		"status":    "up",
+		"status":    bson.M{"$in": bson.A{"up", "degraded"}},
 	}
 
 	upCount, err := collection.CountDocuments(ctx, upFilter)
 	if err != nil {
-		return 0.0
+		return 0.0, 0.0
+	}
+
+	degradedFilter := bson.M{
+		"api_name":  apiName,
+		"timestamp": bson.M{"$gte": since},
+		"status":    "degraded",
+	}
+
+	degradedCount, err := collection.CountDocuments(ctx, degradedFilter)
+	if err != nil {
+		degradedCount = 0
 	}
 
-	return float64(upCount) / float64(total) * 100.0
+	return float64(upCount) / float64(total) * 100.0, float64(degradedCount) / float64(total) * 100.0
+}
+
+// sendAlert sends a one-off event alert, such as a certificate or DNS change.
+// Events have nothing to wait for, so they are stored resolved; only outage
+// and degraded alerts stay unresolved while their condition lasts.
+func (m *Monitor) sendAlert(apiConfig config.APIConfig, alertType, message string) {
+	m.storeAndNotify(apiConfig, models.Alert{
+		APIName:   apiConfig.Name,
+		Type:      alertType,
+		Severity:  alertSeverity(alertType),
+		Message:   message,
+		Timestamp: time.Now(),
+		Resolved:  true,
+	}, nil)
 }
 
-func (m *Monitor) sendAlert(apiName, alertType, message string) {
-	alert := models.Alert{
-		APIName:   apiName,
+// sendContentAlert sends a content tracking event alert like sendAlert. diff,
+// if any, is stored on the alert and appended to the notification.
+func (m *Monitor) sendContentAlert(apiConfig config.APIConfig, alertType, message, diff string) {
+	m.storeAndNotify(apiConfig, models.Alert{
+		APIName:   apiConfig.Name,
 		Type:      alertType,
+		Severity:  alertSeverity(alertType),
 		Message:   message,
 		Timestamp: time.Now(),
-		Resolved:  alertType == "up",
+		Diff:      diff,
+		Resolved:  true,
+	}, nil)
+}
+
+// alertSeverity returns the default severity of an alert type.
+func alertSeverity(alertType string) string {
+	switch alertType {
+	case "up":
+		return severityInfo
+	case "down", "timeout":
+		return severityCritical
+	default:
+		return severityWarning
 	}
+}
 
+// storeAndNotify records an alert and sends it to the monitor's notification
+// channels. incident is the outage the alert belongs to, if any.
+func (m *Monitor) storeAndNotify(apiConfig config.APIConfig, alert models.Alert, incident *models.Incident) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	collection := m.db.GetCollection("alerts")
-	_, err := collection.InsertOne(ctx, alert)
+	result, err := collection.InsertOne(ctx, alert)
 	if err != nil {
 		log.Printf("Error storing alert: %v", err)
+	} else {
+		alert.ID = result.InsertedID.(primitive.ObjectID)
 	}
 
-	go m.notifier.SendAlert(apiName, alertType, message)
+	message := alert.Message
+	if alert.Diff != "" {
+		message += "\n```\n" + alert.Diff + "\n```"
+	}
+	m.notify(apiConfig, webhook.Event{
+		APIName:  alert.APIName,
+		Type:     alert.Type,
+		Severity: alert.Severity,
+		Message:  message,
+		Alert:    &alert,
+		Incident: incident,
+	})
+}
+
+// notify hands event to the notifier, which picks the channels from the
+// monitor's notify rules, without waiting for delivery.
+func (m *Monitor) notify(apiConfig config.APIConfig, event webhook.Event) {
+	event.Timestamp = time.Now()
+	go m.notifier.SendAlert(event, apiConfig.Notify)
 }
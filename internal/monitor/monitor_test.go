@@ -0,0 +1,125 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+)
+
+func TestMatchStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		apiConfig config.APIConfig
+		code      int
+		wantRule  string
+		wantErr   string
+	}{
+		{
+			name:      "expected_status",
+			apiConfig: config.APIConfig{ExpectedStatus: 200},
+			code:      200,
+			wantRule:  "200",
+		},
+		{
+			name:      "expected_status mismatch",
+			apiConfig: config.APIConfig{ExpectedStatus: 200},
+			code:      204,
+			wantErr:   "unexpected status code: 204, expected: 200",
+		},
+		{
+			name:      "range lower bound",
+			apiConfig: config.APIConfig{ExpectedStatuses: "200-299,401"},
+			code:      200,
+			wantRule:  "200-299",
+		},
+		{
+			name:      "range upper bound",
+			apiConfig: config.APIConfig{ExpectedStatuses: "200-299,401"},
+			code:      299,
+			wantRule:  "200-299",
+		},
+		{
+			name:      "single code in list",
+			apiConfig: config.APIConfig{ExpectedStatuses: "200-299,401"},
+			code:      401,
+			wantRule:  "401",
+		},
+		{
+			name:      "first matching rule wins",
+			apiConfig: config.APIConfig{ExpectedStatuses: "204,200-299"},
+			code:      204,
+			wantRule:  "204",
+		},
+		{
+			name:      "outside every range",
+			apiConfig: config.APIConfig{ExpectedStatuses: "200-299,401"},
+			code:      500,
+			wantErr:   "unexpected status code: 500, expected one of: 200-299, 401",
+		},
+		{
+			name:      "single range mismatch",
+			apiConfig: config.APIConfig{ExpectedStatuses: "200-299"},
+			code:      301,
+			wantErr:   "unexpected status code: 301, expected: 200-299",
+		},
+		{
+			name:      "invalid expected_statuses",
+			apiConfig: config.APIConfig{ExpectedStatuses: "299-200"},
+			code:      250,
+			wantErr:   `invalid expected_statuses "299-200": range 299-200 is reversed`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := matchStatus(tt.apiConfig, tt.code)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("matchStatus(%d) = %q, %v; want error %q", tt.code, rule, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("matchStatus(%d): %v", tt.code, err)
+			}
+			if rule != tt.wantRule {
+				t.Errorf("matchStatus(%d) = %q, want %q", tt.code, rule, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestWaitCheckWaitsForInFlightCheck(t *testing.T) {
+	m := newTestMonitor()
+	if !m.startCheck("push") {
+		t.Fatal("startCheck on an idle monitor returned false")
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		m.waitCheck("push")
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("waitCheck returned while a check was in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	m.finishCheck("push")
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("waitCheck did not return after the check finished")
+	}
+
+	if m.startCheck("push") {
+		t.Error("startCheck succeeded while waitCheck holds the monitor")
+	}
+	m.finishCheck("push")
+	if !m.startCheck("push") {
+		t.Error("startCheck failed after the monitor was released")
+	}
+}
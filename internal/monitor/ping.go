@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+	"railway-api-uptime-monitor/internal/models"
+)
+
+// pingProbeGap separates consecutive probes of one check.
+const pingProbeGap = 100 * time.Millisecond
+
+// performPingCheck sends ping.count probes to host:port and marks the check
+// down when the loss exceeds ping.max_loss_percent. The average latency of
+// the answered probes is the response time.
+func (m *Monitor) performPingCheck(apiConfig config.APIConfig) checkResult {
+	timeout := m.checkTimeout(apiConfig)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	address, err := config.HostPort(apiConfig.URL)
+	if err != nil {
+		return checkResult{Status: "down", Err: err}
+	}
+
+	pingConfig := *apiConfig.Ping
+	probeTimeout, err := time.ParseDuration(pingConfig.ProbeTimeout)
+	if err != nil {
+		return checkResult{Status: "down", Err: err}
+	}
+
+	stats := &models.PingStats{}
+	var total time.Duration
+	var lastErr error
+
+	for i := 0; i < pingConfig.Count && ctx.Err() == nil; i++ {
+		if i > 0 {
+			time.Sleep(pingProbeGap)
+		}
+
+		stats.Sent++
+		latency, err := probe(ctx, pingConfig, address, probeTimeout)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		stats.Received++
+		total += latency
+		if stats.MinLatency == 0 || latency < stats.MinLatency {
+			stats.MinLatency = latency
+		}
+		if latency > stats.MaxLatency {
+			stats.MaxLatency = latency
+		}
+	}
+
+	if stats.Received > 0 {
+		stats.AvgLatency = total / time.Duration(stats.Received)
+	}
+	stats.LossPercent = float64(stats.Sent-stats.Received) / float64(stats.Sent) * 100.0
+
+	// Lost probes are already repeated attempts, so ping checks are never
+	// retried as a whole.
+	result := checkResult{Status: "up", ResponseTime: stats.AvgLatency, Ping: stats}
+
+	maxLoss := *pingConfig.MaxLossPercent
+	if stats.LossPercent > maxLoss {
+		result.Status = "down"
+		result.Err = fmt.Errorf("packet loss %.1f%% exceeds %.1f%% (%d/%d probes answered)", stats.LossPercent, maxLoss, stats.Received, stats.Sent)
+		if lastErr != nil {
+			result.Err = fmt.Errorf("%v, last error: %v", result.Err, lastErr)
+		}
+	}
+
+	return result
+}
+
+// probe measures one TCP connect or UDP round trip to address.
+func probe(ctx context.Context, pingConfig config.PingConfig, address string, probeTimeout time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, pingConfig.Protocol, address)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if pingConfig.Protocol == "tcp" {
+		return time.Since(start), nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	payload := pingConfig.Payload
+	if payload == "" {
+		payload = "ping"
+	}
+
+	start = time.Now()
+	if _, err := conn.Write([]byte(payload)); err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, 1500)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, errors.New("empty reply")
+	}
+
+	return time.Since(start), nil
+}
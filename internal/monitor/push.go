@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+	"railway-api-uptime-monitor/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MaxHeartbeatLogBytes caps the log snippet stored with a heartbeat.
+const MaxHeartbeatLogBytes = 4 << 10
+
+// RecordHeartbeat stores a heartbeat of a push monitor as a health check.
+// failed marks a heartbeat reporting that the job failed; logText is an
+// optional log snippet sent with it. It waits for a missed-heartbeat check of
+// the monitor that is in flight, so the two never update its status at once.
+func (m *Monitor) RecordHeartbeat(apiConfig config.APIConfig, failed bool, logText string) {
+	m.waitCheck(apiConfig.Name)
+	defer m.finishCheck(apiConfig.Name)
+
+	result := checkResult{
+		Status:    "up",
+		Attempts:  1,
+		Log:       truncate([]byte(logText), MaxHeartbeatLogBytes),
+		Heartbeat: true,
+	}
+	if failed {
+		result.Status = "down"
+		result.Err = errors.New("job reported a failure")
+	}
+
+	m.recordResult(apiConfig, result)
+}
+
+// missedHeartbeat returns a failed result when a push monitor has gone a full
+// period plus grace since its last heartbeat or missed check. Monitors that
+// never received a heartbeat count from their creation.
+func (m *Monitor) missedHeartbeat(apiConfig config.APIConfig) (checkResult, bool) {
+	if apiConfig.Push == nil {
+		return checkResult{}, false
+	}
+
+	deadline, err := apiConfig.Push.Deadline()
+	if err != nil {
+		log.Printf("Skipping push monitor %s: %v", apiConfig.Name, err)
+		return checkResult{}, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	since := apiConfig.CreatedAt
+	var status models.APIStatus
+	err = m.db.GetCollection("api_status").FindOne(ctx, bson.M{"name": apiConfig.Name}).Decode(&status)
+	switch {
+	case err == nil:
+		since = status.LastChecked
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		log.Printf("Error loading status of %s: %v", apiConfig.Name, err)
+		return checkResult{}, false
+	}
+
+	return overdueHeartbeat(deadline, since, time.Now())
+}
+
+// overdueHeartbeat returns a failed result when deadline has passed between
+// since and now.
+func overdueHeartbeat(deadline time.Duration, since, now time.Time) (checkResult, bool) {
+	if now.Sub(since) < deadline {
+		return checkResult{}, false
+	}
+
+	return checkResult{
+		Status:   "down",
+		Attempts: 1,
+		Err:      fmt.Errorf("no heartbeat received within %s", deadline),
+	}, true
+}
@@ -0,0 +1,55 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+)
+
+func TestMissedHeartbeatOpensOutage(t *testing.T) {
+	m := newTestMonitor()
+	m.config.DowntimeThreshold = 3
+
+	apiConfig := config.APIConfig{
+		Name: "Nightly backup",
+		Type: config.TypePush,
+		Push: &config.PushConfig{Period: "24h", Grace: "30m"},
+	}
+	deadline, err := apiConfig.Push.Deadline()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastChecked := time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC)
+
+	if _, missed := overdueHeartbeat(deadline, lastChecked, lastChecked.Add(24*time.Hour)); missed {
+		t.Error("heartbeat counted as missed within the grace period")
+	}
+
+	result, missed := overdueHeartbeat(deadline, lastChecked, lastChecked.Add(24*time.Hour+30*time.Minute))
+	if !missed {
+		t.Fatal("heartbeat not counted as missed after period plus grace")
+	}
+	if result.Status != "down" {
+		t.Errorf("missed heartbeat status = %q, want down", result.Status)
+	}
+
+	alert, ok := m.outageAlert(apiConfig, result, 1)
+	if !ok {
+		t.Fatal("first missed heartbeat did not open an outage alert")
+	}
+	if alert.Type != "down" || alert.Severity != severityCritical {
+		t.Errorf("alert = %s/%s, want down/critical", alert.Type, alert.Severity)
+	}
+	if want := "no heartbeat received within 24h30m0s"; alert.Message != want {
+		t.Errorf("alert message = %q, want %q", alert.Message, want)
+	}
+
+	polled := config.APIConfig{Name: "orders", Type: config.TypeHTTP}
+	if _, ok := m.outageAlert(polled, checkResult{Status: "down"}, 1); ok {
+		t.Error("first failed HTTP check opened an alert below DOWNTIME_THRESHOLD")
+	}
+	if _, ok := m.outageAlert(polled, checkResult{Status: "down"}, 3); !ok {
+		t.Error("HTTP check at DOWNTIME_THRESHOLD did not open an alert")
+	}
+}
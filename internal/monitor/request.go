@@ -0,0 +1,54 @@
+package monitor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"railway-api-uptime-monitor/internal/config"
+)
+
+const userAgent = "Railway-API-Uptime-Monitor/1.0"
+
+// buildRequest creates the request described by a monitor: its method,
+// query parameters, headers and body are sent exactly as configured.
+func buildRequest(ctx context.Context, apiConfig config.APIConfig) (*http.Request, error) {
+	u, err := url.Parse(apiConfig.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(apiConfig.Query) > 0 {
+		query := u.Query()
+		for key, value := range apiConfig.Query {
+			query.Set(key, value)
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	var body io.Reader
+	if apiConfig.Body != "" {
+		body = strings.NewReader(apiConfig.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, apiConfig.Method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+	if apiConfig.ContentType != "" {
+		req.Header.Set("Content-Type", apiConfig.ContentType)
+	}
+	for key, value := range apiConfig.Headers {
+		if strings.EqualFold(key, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(key, value)
+	}
+
+	return req, nil
+}
@@ -0,0 +1,91 @@
+package monitor
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+)
+
+// maxRetryBackoff caps the exponential delay between attempts.
+const maxRetryBackoff = 30 * time.Second
+
+// checkWithRetries runs the check, retrying transient failures with
+// exponential backoff. The returned result is that of the last attempt,
+// annotated with the attempt count and every failed attempt's error.
+func (m *Monitor) checkWithRetries(apiConfig config.APIConfig) checkResult {
+	retries, backoff := m.retryPolicy(apiConfig)
+
+	var attemptErrors []string
+	for attempt := 1; ; attempt++ {
+		result := m.perform(apiConfig)
+		if result.Err != nil {
+			attemptErrors = append(attemptErrors, fmt.Sprintf("attempt %d: %v", attempt, result.Err))
+		}
+
+		if result.Status == "up" || !result.transient || attempt > retries {
+			result.Attempts = attempt
+			result.AttemptErrors = attemptErrors
+			return result
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+// retryPolicy returns the number of retries and the initial backoff for a
+// monitor, falling back to MAX_RETRIES and RETRY_BACKOFF_MS.
+func (m *Monitor) retryPolicy(apiConfig config.APIConfig) (int, time.Duration) {
+	retries := m.config.MaxRetries
+	if apiConfig.Retries != nil {
+		retries = *apiConfig.Retries
+	}
+
+	backoff := time.Duration(m.config.RetryBackoffMs) * time.Millisecond
+	if apiConfig.RetryBackoff != "" {
+		if d, err := time.ParseDuration(apiConfig.RetryBackoff); err == nil {
+			backoff = d
+		}
+	}
+
+	return retries, backoff
+}
+
+// isTimeout reports whether err comes from a deadline being exceeded.
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isTransient reports whether a transport error may go away on its own.
+// Certificate problems are permanent until someone fixes the endpoint.
+func isTransient(err error) bool {
+	var certInvalid x509.CertificateInvalidError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	if errors.As(err, &certInvalid) || errors.As(err, &unknownAuthority) || errors.As(err, &hostname) {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
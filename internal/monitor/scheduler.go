@@ -0,0 +1,184 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+
+	"github.com/robfig/cron/v3"
+)
+
+// schedulerTick is how often the scheduler looks for due monitors.
+const schedulerTick = time.Second
+
+// pushSweepInterval is how often push monitors, which are not polled, are
+// checked for missed heartbeats.
+const pushSweepInterval = "30s"
+
+// Scheduler runs each monitor on its own interval. It reloads the monitor
+// list periodically so monitors added, changed or removed through the API are
+// picked up without a restart.
+type Scheduler struct {
+	monitor *Monitor
+	entries map[string]*scheduleEntry
+	ctx     context.Context
+	cancel  context.CancelFunc
+	ticks   sync.WaitGroup
+	done    chan struct{}
+}
+
+type scheduleEntry struct {
+	api      config.APIConfig
+	interval string
+	schedule cron.Schedule
+	next     time.Time
+}
+
+func NewScheduler(m *Monitor) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		monitor: m,
+		entries: make(map[string]*scheduleEntry),
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+}
+
+func (s *Scheduler) Start() {
+	go s.run()
+}
+
+// Stop halts scheduling and waits for running checks to finish. Checks still
+// queued for a worker are skipped.
+func (s *Scheduler) Stop() {
+	s.cancel()
+	<-s.done
+	s.ticks.Wait()
+}
+
+func (s *Scheduler) run() {
+	defer close(s.done)
+
+	reloadEvery := time.Duration(s.monitor.config.ReloadSeconds) * time.Second
+	if reloadEvery <= 0 {
+		reloadEvery = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(schedulerTick)
+	defer ticker.Stop()
+	reload := time.NewTicker(reloadEvery)
+	defer reload.Stop()
+
+	s.reload()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-reload.C:
+			s.reload()
+		case now := <-ticker.C:
+			s.dispatchDue(now)
+		}
+	}
+}
+
+// reload syncs the schedule with the monitors collection. Monitors whose
+// interval is unchanged keep their next run time.
+func (s *Scheduler) reload() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	apis, err := s.monitor.db.ListMonitors(ctx)
+	if err != nil {
+		log.Printf("Error loading monitors: %v", err)
+		return
+	}
+
+	now := time.Now()
+	entries := make(map[string]*scheduleEntry, len(apis))
+
+	for _, api := range apis {
+		interval := api.Interval
+		if interval == "" {
+			interval = s.monitor.config.CheckInterval
+		}
+		if api.Type == config.TypePush {
+			interval = pushSweepInterval
+		}
+
+		if existing, ok := s.entries[api.Name]; ok && existing.interval == interval {
+			existing.api = api
+			entries[api.Name] = existing
+			continue
+		}
+
+		schedule, err := config.ParseInterval(interval)
+		if err != nil {
+			log.Printf("Skipping monitor %s: %v", api.Name, err)
+			continue
+		}
+
+		// Spread the first run of new monitors so a reload or restart does
+		// not fire every check at the same moment.
+		period := schedule.Next(now).Sub(now)
+		entries[api.Name] = &scheduleEntry{
+			api:      api,
+			interval: interval,
+			schedule: schedule,
+			next:     now.Add(s.jitter(period)),
+		}
+	}
+
+	for name := range s.entries {
+		if _, ok := entries[name]; !ok {
+			log.Printf("Monitor %s removed from schedule", name)
+		}
+	}
+
+	s.entries = entries
+}
+
+func (s *Scheduler) dispatchDue(now time.Time) {
+	var due []config.APIConfig
+	for _, entry := range s.entries {
+		if now.Before(entry.next) {
+			continue
+		}
+
+		next := entry.schedule.Next(now)
+		entry.next = next.Add(s.jitter(next.Sub(now)))
+		due = append(due, entry.api)
+	}
+
+	if len(due) == 0 {
+		return
+	}
+
+	s.ticks.Add(1)
+	go func() {
+		defer s.ticks.Done()
+
+		summary := s.monitor.RunChecks(s.ctx, due)
+		log.Printf("Tick complete: %d checks ran, %d skipped, %d still running from previous tick",
+			summary.Ran, summary.Skipped, summary.StillRunning)
+	}()
+}
+
+// jitter returns a random delay of up to a tenth of period, capped by
+// MAX_JITTER_SECONDS.
+func (s *Scheduler) jitter(period time.Duration) time.Duration {
+	limit := period / 10
+	if maxJitter := time.Duration(s.monitor.config.MaxJitterSeconds) * time.Second; limit > maxJitter {
+		limit = maxJitter
+	}
+	if limit <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(limit)))
+}
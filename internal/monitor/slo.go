@@ -0,0 +1,59 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+	"railway-api-uptime-monitor/internal/models"
+)
+
+// Alert severities.
+const (
+	severityInfo     = "info"
+	severityWarning  = "warning"
+	severityCritical = "critical"
+)
+
+// applySLO marks a successful check "degraded" when its response time
+// reaches the monitor's warning or critical threshold.
+func applySLO(apiConfig config.APIConfig, result *checkResult) {
+	if apiConfig.SLO == nil || result.Status != "up" {
+		return
+	}
+
+	warning, critical, err := apiConfig.SLO.Thresholds()
+	if err != nil {
+		return
+	}
+
+	responseTime := result.ResponseTime.Round(time.Millisecond)
+	switch {
+	case critical > 0 && result.ResponseTime >= critical:
+		result.Status = "degraded"
+		result.Severity = severityCritical
+		result.Err = fmt.Errorf("response time %s exceeds the critical threshold of %s", responseTime, critical)
+	case warning > 0 && result.ResponseTime >= warning:
+		result.Status = "degraded"
+		result.Severity = severityWarning
+		result.Err = fmt.Errorf("response time %s exceeds the warning threshold of %s", responseTime, warning)
+	}
+}
+
+// checkDegraded keeps the monitor's degraded alert open while it is degraded
+// and resolves it once response times are back to normal.
+func (m *Monitor) checkDegraded(apiConfig config.APIConfig, previous models.APIStatus, result checkResult) {
+	switch {
+	case result.Status == "degraded":
+		m.openAlert(apiConfig, degradedAlertTypes, models.Alert{
+			APIName:  apiConfig.Name,
+			Type:     "degraded",
+			Severity: result.Severity,
+			Message:  result.Err.Error(),
+		}, nil)
+	case previous.Status != "up":
+		// A degraded alert may outlive a failure in between, so it is
+		// looked up whenever the monitor was not simply up.
+		m.resolveAlert(apiConfig, degradedAlertTypes, "up", "Response times are back to normal", nil)
+	}
+}
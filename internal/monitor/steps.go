@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"railway-api-uptime-monitor/internal/config"
+	"railway-api-uptime-monitor/internal/jsonpath"
+	"railway-api-uptime-monitor/internal/models"
+)
+
+// performStepsCheck runs the requests of a "steps" monitor in order, feeding
+// the values extracted from each response into the following steps. It stops
+// at the first failed step. The response time is the sum of the steps.
+func (m *Monitor) performStepsCheck(apiConfig config.APIConfig) checkResult {
+	result := checkResult{Status: "up"}
+	vars := map[string]string{}
+
+	for i, step := range apiConfig.Steps {
+		name := step.Name
+		if name == "" {
+			name = fmt.Sprintf("step %d", i+1)
+		}
+
+		stepResult := m.performStep(apiConfig, i, vars)
+		result.ResponseTime += stepResult.ResponseTime
+		result.StatusCode = stepResult.StatusCode
+
+		record := models.StepResult{
+			Name:         name,
+			Status:       stepResult.Status,
+			StatusCode:   stepResult.StatusCode,
+			ResponseTime: stepResult.ResponseTime,
+		}
+		if stepResult.Err != nil {
+			record.ErrorMessage = stepResult.Err.Error()
+		}
+		result.Steps = append(result.Steps, record)
+
+		if stepResult.Status != "up" {
+			result.Status = stepResult.Status
+			result.Err = fmt.Errorf("step %d (%s): %v", i+1, name, stepResult.Err)
+			result.transient = stepResult.transient
+			break
+		}
+	}
+
+	return result
+}
+
+// performStep runs step i with the variables extracted so far and adds the
+// step's own extractions to vars.
+func (m *Monitor) performStep(apiConfig config.APIConfig, i int, vars map[string]string) checkResult {
+	request, err := apiConfig.StepRequest(i, vars)
+	if err != nil {
+		return checkResult{Status: "down", Err: err}
+	}
+
+	result := m.performHealthCheck(request)
+	if result.Status != "up" {
+		return result
+	}
+
+	for _, extraction := range apiConfig.Steps[i].Extract {
+		value, err := extract(extraction, result.header, result.body)
+		if err != nil {
+			result.Status = "down"
+			result.Err = fmt.Errorf("extracting %s: %v", extraction.Name, err)
+			return result
+		}
+		vars[extraction.Name] = value
+	}
+
+	return result
+}
+
+// extract reads the value of an extraction from a response.
+func extract(extraction config.Extraction, header http.Header, body []byte) (string, error) {
+	switch extraction.Type {
+	case config.ExtractJSONPath:
+		value, found, err := jsonpath.LookupBytes(body, extraction.Path)
+		if err != nil {
+			return "", err
+		}
+		if !found {
+			return "", fmt.Errorf("path %s not found", extraction.Path)
+		}
+		return jsonpath.Format(value), nil
+
+	case config.ExtractHeader:
+		values, ok := header[http.CanonicalHeaderKey(extraction.Path)]
+		if !ok || len(values) == 0 {
+			return "", fmt.Errorf("header %s not present", extraction.Path)
+		}
+		return values[0], nil
+
+	case config.ExtractRegex:
+		re, err := regexp.Compile(extraction.Path)
+		if err != nil {
+			return "", err
+		}
+		match := re.FindSubmatch(body)
+		if match == nil {
+			return "", fmt.Errorf("body does not match %q", extraction.Path)
+		}
+		if len(match) > 1 {
+			return string(match[1]), nil
+		}
+		return string(match[0]), nil
+	}
+
+	return "", fmt.Errorf("unknown extraction type %q", extraction.Type)
+}
+
+// failedStep returns the name of the step that failed, if any.
+func failedStep(steps []models.StepResult) string {
+	for _, step := range steps {
+		if step.Status != "up" {
+			return step.Name
+		}
+	}
+	return ""
+}
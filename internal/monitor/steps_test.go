@@ -0,0 +1,45 @@
+package monitor
+
+import (
+	"net/http"
+	"testing"
+
+	"railway-api-uptime-monitor/internal/config"
+)
+
+func TestExtract(t *testing.T) {
+	header := http.Header{}
+	header.Set("Location", "https://api.example.com/orders/12345678901234567890")
+	body := []byte(`{"id":12345678901234567890,"total":12.50,"token":"abc"}`)
+
+	tests := []struct {
+		extraction config.Extraction
+		want       string
+		wantErr    bool
+	}{
+		{extraction: config.Extraction{Type: config.ExtractJSONPath, Path: "$.id"}, want: "12345678901234567890"},
+		{extraction: config.Extraction{Type: config.ExtractJSONPath, Path: "$.total"}, want: "12.50"},
+		{extraction: config.Extraction{Type: config.ExtractJSONPath, Path: "$.token"}, want: "abc"},
+		{extraction: config.Extraction{Type: config.ExtractJSONPath, Path: "$.missing"}, wantErr: true},
+		{extraction: config.Extraction{Type: config.ExtractHeader, Path: "location"}, want: "https://api.example.com/orders/12345678901234567890"},
+		{extraction: config.Extraction{Type: config.ExtractRegex, Path: `"token":"(\w+)"`}, want: "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.extraction.Type+" "+tt.extraction.Path, func(t *testing.T) {
+			got, err := extract(tt.extraction, header, body)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("extract = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extract: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("extract = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"regexp"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+)
+
+// maxTCPResponseBytes caps how much is read while waiting for tcp.expect.
+const maxTCPResponseBytes = 64 << 10
+
+// performTCPCheck connects to host:port, optionally upgrades to TLS, writes
+// tcp.send and waits for a response matching tcp.expect.
+func (m *Monitor) performTCPCheck(apiConfig config.APIConfig) checkResult {
+	timeout := m.checkTimeout(apiConfig)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	address, err := config.HostPort(apiConfig.URL)
+	if err != nil {
+		return checkResult{Status: "down", Err: err}
+	}
+
+	tcpConfig := config.TCPConfig{}
+	if apiConfig.TCP != nil {
+		tcpConfig = *apiConfig.TCP
+	}
+
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return failedResult(err, time.Since(start), timeout)
+	}
+	defer conn.Close()
+
+	result := checkResult{Status: "up"}
+	result.Timings.TCPConnect = time.Since(start)
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if tcpConfig.TLS {
+		host, _, _ := net.SplitHostPort(address)
+		tlsStart := time.Now()
+		tlsConn := tls.Client(conn, &tls.Config{
+			ServerName:         host,
+			InsecureSkipVerify: true,
+			VerifyConnection:   m.verifyPeerChain(host),
+		})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			failed := failedResult(err, time.Since(start), timeout)
+			failed.Timings = result.Timings
+			failed.TLS = failedTLSInfo(err)
+			return failed
+		}
+		result.Timings.TLSHandshake = time.Since(tlsStart)
+		result.TLS = tlsInfo(tlsConn.ConnectionState().PeerCertificates, nil)
+		conn = tlsConn
+	}
+
+	if tcpConfig.Send != "" {
+		if _, err := conn.Write([]byte(tcpConfig.Send)); err != nil {
+			failed := failedResult(fmt.Errorf("error sending payload: %w", err), time.Since(start), timeout)
+			failed.Timings = result.Timings
+			return failed
+		}
+	}
+
+	if tcpConfig.Expect != "" {
+		firstByte := time.Now()
+		if err := expectResponse(conn, tcpConfig.Expect); err != nil {
+			failed := failedResult(err, time.Since(start), timeout)
+			failed.Timings = result.Timings
+			return failed
+		}
+		result.Timings.TimeToFirstByte = time.Since(firstByte)
+	}
+
+	result.ResponseTime = time.Since(start)
+	return result
+}
+
+// expectResponse reads from conn until what has been received matches
+// pattern, the connection closes or the deadline passes.
+func expectResponse(conn net.Conn, pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+
+	var received []byte
+	buf := make([]byte, 4096)
+	for len(received) < maxTCPResponseBytes {
+		n, readErr := conn.Read(buf)
+		received = append(received, buf[:n]...)
+		if re.Match(received) {
+			return nil
+		}
+		if readErr != nil {
+			if isTimeout(readErr) {
+				return fmt.Errorf("no response matching %q before timeout, got %q: %w", pattern, truncate(received, 200), readErr)
+			}
+			break
+		}
+	}
+
+	return fmt.Errorf("response did not match %q, got %q", pattern, truncate(received, 200))
+}
+
+func truncate(b []byte, n int) string {
+	if len(b) > n {
+		return string(b[:n]) + "..."
+	}
+	return string(b)
+}
@@ -0,0 +1,84 @@
+package monitor
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// phaseTimings breaks an HTTP check down into its phases. Phases that did not
+// happen, such as DNS on a reused connection, are left at zero.
+type phaseTimings struct {
+	DNSLookup       time.Duration
+	TCPConnect      time.Duration
+	TLSHandshake    time.Duration
+	TimeToFirstByte time.Duration
+	ContentTransfer time.Duration
+
+	// WebSocket checks record the upgrade handshake and the round trip of
+	// the test message instead.
+	Handshake time.Duration
+	RoundTrip time.Duration
+}
+
+// timingTrace records phase boundaries through an httptrace.ClientTrace.
+type timingTrace struct {
+	mu           sync.Mutex
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	gotConn      time.Time
+	firstByte    time.Time
+}
+
+func (t *timingTrace) clientTrace() *httptrace.ClientTrace {
+	record := func(field *time.Time) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		*field = time.Now()
+	}
+
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { record(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { record(&t.dnsDone) },
+		ConnectStart: func(string, string) {
+			// Dual-stack dialing may start several connects; keep the first.
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+		},
+		ConnectDone:          func(string, string, error) { record(&t.connectDone) },
+		TLSHandshakeStart:    func() { record(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { record(&t.tlsDone) },
+		GotConn:              func(httptrace.GotConnInfo) { record(&t.gotConn) },
+		GotFirstResponseByte: func() { record(&t.firstByte) },
+	}
+}
+
+// timings converts the recorded boundaries into phase durations. end marks
+// the moment the response body was fully read.
+func (t *timingTrace) timings(end time.Time) phaseTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return phaseTimings{
+		DNSLookup:       between(t.dnsStart, t.dnsDone),
+		TCPConnect:      between(t.connectStart, t.connectDone),
+		TLSHandshake:    between(t.tlsStart, t.tlsDone),
+		TimeToFirstByte: between(t.gotConn, t.firstByte),
+		ContentTransfer: between(t.firstByte, end),
+	}
+}
+
+func between(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
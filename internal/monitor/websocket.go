@@ -0,0 +1,129 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+
+	"github.com/gorilla/websocket"
+)
+
+// performWebSocketCheck completes the upgrade handshake and, when
+// websocket.send is set, sends it and waits for a reply matching
+// websocket.expect. Messages that do not match are skipped until the timeout.
+func (m *Monitor) performWebSocketCheck(apiConfig config.APIConfig) checkResult {
+	timeout := m.checkTimeout(apiConfig)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	wsConfig := config.WebSocketConfig{}
+	if apiConfig.WebSocket != nil {
+		wsConfig = *apiConfig.WebSocket
+	}
+
+	var expect *regexp.Regexp
+	if wsConfig.Expect != "" {
+		var err error
+		if expect, err = regexp.Compile(wsConfig.Expect); err != nil {
+			return checkResult{Status: "down", Err: err}
+		}
+	}
+
+	header := http.Header{}
+	header.Set("User-Agent", userAgent)
+	for key, value := range apiConfig.Headers {
+		header.Set(key, value)
+	}
+
+	target, err := url.Parse(apiConfig.URL)
+	if err != nil {
+		return checkResult{Status: "down", Err: err}
+	}
+
+	dialer := websocket.Dialer{
+		Proxy: http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+			VerifyConnection:   m.verifyPeerChain(target.Hostname()),
+		},
+	}
+
+	start := time.Now()
+	conn, resp, err := dialer.DialContext(ctx, apiConfig.URL, header)
+	handshake := time.Since(start)
+	if err != nil {
+		result := failedResult(err, handshake, timeout)
+		result.TLS = failedTLSInfo(err)
+		if errors.Is(err, websocket.ErrBadHandshake) && resp != nil {
+			result.StatusCode = resp.StatusCode
+			result.Err = fmt.Errorf("upgrade rejected with status %d", resp.StatusCode)
+			result.transient = resp.StatusCode >= 500
+		}
+		return result
+	}
+	defer conn.Close()
+
+	result := checkResult{Status: "up", StatusCode: resp.StatusCode}
+	result.Timings.Handshake = handshake
+	if tlsConn, ok := conn.UnderlyingConn().(*tls.Conn); ok {
+		result.TLS = tlsInfo(tlsConn.ConnectionState().PeerCertificates, nil)
+	}
+
+	if wsConfig.Send != "" {
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetWriteDeadline(deadline)
+			conn.SetReadDeadline(deadline)
+		}
+
+		sent := time.Now()
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(wsConfig.Send)); err != nil {
+			failed := failedResult(fmt.Errorf("error sending message: %w", err), time.Since(start), timeout)
+			failed.StatusCode = result.StatusCode
+			failed.Timings = result.Timings
+			failed.TLS = result.TLS
+			return failed
+		}
+
+		if err := expectMessage(conn, expect); err != nil {
+			failed := failedResult(err, time.Since(start), timeout)
+			failed.StatusCode = result.StatusCode
+			failed.Timings = result.Timings
+			failed.TLS = result.TLS
+			return failed
+		}
+		result.Timings.RoundTrip = time.Since(sent)
+	}
+
+	conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(time.Second))
+
+	result.ResponseTime = time.Since(start)
+	return result
+}
+
+// expectMessage reads messages until one matches expect, or any message when
+// expect is nil.
+func expectMessage(conn *websocket.Conn, expect *regexp.Regexp) error {
+	var last []byte
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if expect == nil {
+				return fmt.Errorf("no reply received: %w", err)
+			}
+			return fmt.Errorf("no reply matching %q, last message %q: %w", expect, truncate(last, 200), err)
+		}
+		if expect == nil || expect.Match(message) {
+			return nil
+		}
+		last = message
+	}
+}
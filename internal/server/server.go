@@ -7,6 +7,7 @@ import (
 	"railway-api-uptime-monitor/internal/config"
 	"railway-api-uptime-monitor/internal/database"
 	"railway-api-uptime-monitor/internal/handlers"
+	"railway-api-uptime-monitor/internal/monitor"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,7 +18,7 @@ type Server struct {
 	server *http.Server
 }
 
-func New(db *database.Database, cfg *config.Config) *Server {
+func New(db *database.Database, cfg *config.Config, m *monitor.Monitor) *Server {
 	// Set Gin mode
 	if cfg.Port != "8080" { // Assume production if not default port
 		gin.SetMode(gin.ReleaseMode)
@@ -26,7 +27,7 @@ func New(db *database.Database, cfg *config.Config) *Server {
 	router := gin.Default()
 
 	// Initialize handlers
-	h := handlers.New(db)
+	h := handlers.New(db, m)
 
 	// Middleware
 	router.Use(corsMiddleware())
@@ -35,6 +36,7 @@ func New(db *database.Database, cfg *config.Config) *Server {
 
 	// Serve static files
 	router.Static("/static", "./web/static")
+	router.SetFuncMap(templateFuncs)
 	router.LoadHTMLGlob("web/templates/*")
 
 	// Routes
@@ -72,7 +74,24 @@ func setupRoutes(router *gin.Engine, h *handlers.Handler) {
 		api.GET("/status/:name", h.GetAPIStatus)
 		api.GET("/logs/:name", h.GetAPILogs)
 		api.GET("/alerts", h.GetAlerts)
+		api.GET("/incidents", h.ListIncidents)
+		api.GET("/incidents/:id", h.GetIncident)
+		api.POST("/incidents/:id/ack", h.AcknowledgeIncident)
+		api.POST("/incidents/:id/comments", h.CommentOnIncident)
+		api.POST("/incidents/:id/resolve", h.ResolveIncident)
 		api.GET("/stats", h.GetStats)
+		api.GET("/stats/:name", h.GetAPIStats)
+
+		api.GET("/monitors", h.ListMonitors)
+		api.POST("/monitors", h.CreateMonitor)
+		api.GET("/monitors/:name", h.GetMonitor)
+		api.PUT("/monitors/:name", h.UpdateMonitor)
+		api.PATCH("/monitors/:name", h.PatchMonitor)
+		api.DELETE("/monitors/:name", h.DeleteMonitor)
+		api.GET("/channels", h.ListChannels)
+
+		api.POST("/heartbeat/:token", h.Heartbeat)
+		api.POST("/heartbeat/:token/fail", h.HeartbeatFail)
 	}
 }
 
@@ -81,7 +100,7 @@ func corsMiddleware() gin.HandlerFunc {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
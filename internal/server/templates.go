@@ -0,0 +1,47 @@
+package server
+
+import (
+	"html/template"
+	"time"
+)
+
+// templateFuncs are the helpers the dashboard template uses to total and
+// average the monitor statuses.
+var templateFuncs = template.FuncMap{
+	"add": add,
+	"div": div,
+}
+
+// add returns a + b. Two integers add up to an integer so counters print
+// without decimals; anything else is added as float64.
+func add(a, b interface{}) interface{} {
+	x, xInt := a.(int)
+	y, yInt := b.(int)
+	if xInt && yInt {
+		return x + y
+	}
+	return toFloat(a) + toFloat(b)
+}
+
+// div returns a / b as float64, or 0 when b is zero.
+func div(a, b interface{}) float64 {
+	divisor := toFloat(b)
+	if divisor == 0 {
+		return 0
+	}
+	return toFloat(a) / divisor
+}
+
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	case time.Duration:
+		return float64(n)
+	}
+	return 0
+}
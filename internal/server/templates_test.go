@@ -0,0 +1,134 @@
+package server
+
+import (
+	"bytes"
+	"html/template"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"railway-api-uptime-monitor/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"add ints", add(2, 1), 3},
+		{"add floats", add(99.5, 0.25), 99.75},
+		{"add int and float", add(0, 12.5), 12.5},
+		{"div floats", div(199.0, 2), 99.5},
+		{"div nanoseconds", div(int64(1500*time.Millisecond), 1000000), 1500.0},
+		{"div by zero", div(10.0, 0), 0.0},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v (%T), want %v (%T)", tt.name, tt.got, tt.got, tt.want, tt.want)
+		}
+	}
+}
+
+// renderDashboard executes dashboard.html the way the router does.
+func renderDashboard(t *testing.T, data gin.H) string {
+	t.Helper()
+
+	templates, err := template.New("").Funcs(templateFuncs).ParseGlob("../../web/templates/*")
+	if err != nil {
+		t.Fatalf("parsing templates: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := templates.ExecuteTemplate(&out, "dashboard.html", data); err != nil {
+		t.Fatalf("rendering dashboard: %v", err)
+	}
+	return out.String()
+}
+
+// statCard returns the number shown on the dashboard stat card labelled
+// label.
+func statCard(t *testing.T, page, label string) string {
+	t.Helper()
+
+	re := regexp.MustCompile(`(?s)<div class="stat-number[^"]*">\s*([^<]*?)\s*</div>\s*<div class="stat-label">` + regexp.QuoteMeta(label) + `</div>`)
+	match := re.FindStringSubmatch(page)
+	if match == nil {
+		t.Fatalf("no %q stat card on the dashboard", label)
+	}
+	return match[1]
+}
+
+func TestDashboardTemplate(t *testing.T) {
+	acknowledgedAt := time.Now()
+	page := renderDashboard(t, gin.H{
+		"apis": []models.APIStatus{
+			{Name: "orders", Status: "up", ResponseTime: 120 * time.Millisecond, UptimePercent: 100},
+			{Name: "billing", Status: "down", UptimePercent: 80},
+			{Name: "search", Status: "timeout", UptimePercent: 90},
+			{Name: "catalog", Status: "degraded", Severity: "warning", UptimePercent: 90, DegradedPercent: 12.5},
+		},
+		"incidents": []models.Incident{
+			{
+				APIName:        "billing",
+				Status:         models.IncidentAcknowledged,
+				Summary:        "billing is down: connection refused",
+				StartedAt:      time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+				FailedChecks:   4,
+				AcknowledgedAt: &acknowledgedAt,
+				AcknowledgedBy: "alice",
+			},
+		},
+		"timestamp": "2024-05-01 12:05:00",
+	})
+
+	for label, want := range map[string]string{
+		"Total APIs":    "4",
+		"APIs Up":       "1",
+		"APIs Degraded": "1",
+		"APIs Down":     "2",
+		"Avg Uptime":    "90.0%",
+	} {
+		if got := statCard(t, page, label); got != want {
+			t.Errorf("%s = %q, want %q", label, got, want)
+		}
+	}
+
+	for _, want := range []string{
+		"Open Incidents",
+		"billing is down: connection refused",
+		"Started 2024-05-01 12:00:00 · 4 failed check(s)",
+		"acknowledged by alice",
+		"120ms",
+		`<div class="api-card degraded">`,
+		`<div class="api-status status-degraded">degraded</div>`,
+		"Degraded 12.5% of checks (24h)",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("dashboard does not contain %q", want)
+		}
+	}
+}
+
+func TestDashboardTemplateEmpty(t *testing.T) {
+	// Dashboard passes the decoded slices, which are nil when empty.
+	page := renderDashboard(t, gin.H{
+		"apis":      []models.APIStatus(nil),
+		"incidents": []models.Incident(nil),
+		"timestamp": "2024-05-01 12:05:00",
+	})
+
+	if got := statCard(t, page, "Avg Uptime"); got != "100.0%" {
+		t.Errorf("Avg Uptime = %q, want 100.0%%", got)
+	}
+	if strings.Contains(page, "Open Incidents") {
+		t.Error("dashboard lists incidents when there are none")
+	}
+	if !strings.Contains(page, "No APIs Configured") {
+		t.Error("dashboard does not show the empty state")
+	}
+}
@@ -0,0 +1,327 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+)
+
+const smtpTimeout = 30 * time.Second
+
+const defaultEmailSubject = `{{.Emoji}} {{.APIName}} API Status Change: {{.Type}}`
+
+const defaultEmailText = `{{.APIName}} API Status Change
+
+{{.Message}}
+
+API Name: {{.APIName}}
+Status:   {{.Type}}
+Severity: {{.Severity}}
+Time:     {{.Time}}
+{{- if .Incident}}
+Incident: {{.Incident.ID.Hex}} ({{.Incident.Status}})
+{{- end}}
+`
+
+const defaultEmailHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #333;">
+  <div style="border-left: 4px solid {{.Color}}; padding: 0 1rem;">
+    <h2>{{.Emoji}} {{.APIName}} API Status Change</h2>
+    <p style="white-space: pre-wrap;">{{.Message}}</p>
+    <table cellpadding="4">
+      <tr><th align="left">API Name</th><td>{{.APIName}}</td></tr>
+      <tr><th align="left">Status</th><td>{{.Type}}</td></tr>
+      <tr><th align="left">Severity</th><td>{{.Severity}}</td></tr>
+      <tr><th align="left">Time</th><td>{{.Time}}</td></tr>
+      {{- if .Incident}}
+      <tr><th align="left">Incident</th><td>{{.Incident.ID.Hex}} ({{.Incident.Status}})</td></tr>
+      {{- end}}
+    </table>
+  </div>
+</body>
+</html>
+`
+
+// emailChannel sends notifications by SMTP, as multipart messages with a
+// plaintext and an HTML body.
+type emailChannel struct {
+	addr     string
+	host     string
+	security string
+	username string
+	password string
+	from     string
+	to       []string
+
+	subject *template.Template
+	text    *template.Template
+	html    *htmltemplate.Template
+
+	// dial opens the connection to the server and tlsConfig secures it.
+	// Both can be replaced to talk to a local fake server.
+	dial      func(ctx context.Context, network, addr string) (net.Conn, error)
+	tlsConfig *tls.Config
+}
+
+// emailData is what email templates are executed with: the event plus the
+// styling sendSlackAlert uses for its alert type.
+type emailData struct {
+	Event
+	Color string
+	Emoji string
+	Time  string
+}
+
+func newEmailChannel(channelConfig config.ChannelConfig) (*emailChannel, error) {
+	if channelConfig.Host == "" {
+		return nil, errors.New("host is required")
+	}
+	if _, err := mail.ParseAddress(channelConfig.From); err != nil {
+		return nil, fmt.Errorf("invalid from address %q", channelConfig.From)
+	}
+	if len(channelConfig.To) == 0 {
+		return nil, errors.New("at least one recipient is required")
+	}
+	for _, to := range channelConfig.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return nil, fmt.Errorf("invalid recipient %q", to)
+		}
+	}
+
+	security := strings.ToLower(channelConfig.Security)
+	port := channelConfig.Port
+	switch security {
+	case "", config.SMTPStartTLS:
+		security = config.SMTPStartTLS
+		if port == 0 {
+			port = 587
+		}
+	case config.SMTPTLS:
+		if port == 0 {
+			port = 465
+		}
+	case config.SMTPNone:
+		if port == 0 {
+			port = 25
+		}
+	default:
+		return nil, fmt.Errorf("unknown security %q", channelConfig.Security)
+	}
+
+	subjectText := channelConfig.Subject
+	if subjectText == "" {
+		subjectText = defaultEmailSubject
+	}
+	subject, err := template.New("subject").Funcs(templateFuncs).Parse(subjectText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subject template: %w", err)
+	}
+
+	bodyText, err := templateText(channelConfig, defaultEmailText)
+	if err != nil {
+		return nil, err
+	}
+	text, err := template.New("text").Funcs(templateFuncs).Parse(bodyText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+
+	htmlText := channelConfig.HTMLTemplate
+	if htmlText == "" {
+		htmlText = defaultEmailHTML
+	}
+	html, err := htmltemplate.New("html").Parse(htmlText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid html template: %w", err)
+	}
+
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	return &emailChannel{
+		addr:      net.JoinHostPort(channelConfig.Host, strconv.Itoa(port)),
+		host:      channelConfig.Host,
+		security:  security,
+		username:  channelConfig.Username,
+		password:  channelConfig.Password,
+		from:      channelConfig.From,
+		to:        channelConfig.To,
+		subject:   subject,
+		text:      text,
+		html:      html,
+		dial:      dialer.DialContext,
+		tlsConfig: &tls.Config{ServerName: channelConfig.Host},
+	}, nil
+}
+
+func (c *emailChannel) Send(event Event) error {
+	return c.sendEmailAlert(event)
+}
+
+func (c *emailChannel) sendEmailAlert(event Event) error {
+	message, err := c.message(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), smtpTimeout)
+	defer cancel()
+
+	conn, err := c.dial(ctx, "tcp", c.addr)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	if c.security == config.SMTPTLS {
+		conn = tls.Client(conn, c.tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, c.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if c.security == config.SMTPStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(c.tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	if c.username != "" {
+		// PlainAuth refuses to send credentials over an unencrypted
+		// connection, except to localhost.
+		if err := client.Auth(smtp.PlainAuth("", c.username, c.password, c.host)); err != nil {
+			return err
+		}
+	}
+
+	from, _ := mail.ParseAddress(c.from)
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, to := range c.to {
+		address, _ := mail.ParseAddress(to)
+		if err := client.Rcpt(address.Address); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// message renders the headers and bodies of the email for event.
+func (c *emailChannel) message(event Event) ([]byte, error) {
+	data := emailData{
+		Event: event,
+		Color: "#ff0000", // Red for down
+		Emoji: "❌",
+		Time:  event.Timestamp.UTC().Format("2006-01-02 15:04:05 UTC"),
+	}
+	if event.Type == "up" {
+		data.Color = "#00ff00" // Green for up
+		data.Emoji = "✅"
+	} else if event.Type == "degraded" {
+		data.Color = "#ffcc00" // Yellow for slow responses
+		data.Emoji = "⚠️"
+	} else if event.Type == "incident_resolved" {
+		data.Color = "#439fe0" // Blue for incidents resolved by hand
+		data.Emoji = "ℹ️"
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := c.subject.Execute(&subject, data); err != nil {
+		return nil, fmt.Errorf("rendering subject: %w", err)
+	}
+	if err := c.text.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("rendering text body: %w", err)
+	}
+	if err := c.html.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("rendering html body: %w", err)
+	}
+
+	var message bytes.Buffer
+	body := multipart.NewWriter(&message)
+
+	// Subjects are single-line headers; template newlines are folded.
+	subjectLine := strings.Join(strings.Fields(subject.String()), " ")
+
+	headers := []string{
+		"From: " + c.from,
+		"To: " + strings.Join(c.to, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", subjectLine),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: " + messageID(c.host),
+		"MIME-Version: 1.0",
+		fmt.Sprintf("Content-Type: multipart/alternative; boundary=%q", body.Boundary()),
+	}
+	message.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	if err := writePart(body, "text/plain; charset=utf-8", text.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := writePart(body, "text/html; charset=utf-8", html.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+
+	return message.Bytes(), nil
+}
+
+// writePart adds a quoted-printable part to a multipart message.
+func writePart(body *multipart.Writer, contentType string, content []byte) error {
+	part, err := body.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	w := quotedprintable.NewWriter(part)
+	if _, err := w.Write(content); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func messageID(host string) string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), host)
+}
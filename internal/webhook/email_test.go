@@ -0,0 +1,343 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+)
+
+// smtpSession is what fakeSMTPServer received during one connection.
+type smtpSession struct {
+	tls  bool
+	auth []string
+	from string
+	to   []string
+	data []byte
+}
+
+// fakeSMTPServer accepts one SMTP conversation per connection and reports
+// it on sessions. With implicitTLS the listener is wrapped in TLS; otherwise
+// STARTTLS is offered.
+type fakeSMTPServer struct {
+	listener    net.Listener
+	tlsConfig   *tls.Config
+	implicitTLS bool
+	sessions    chan smtpSession
+}
+
+func startFakeSMTPServer(t *testing.T, cert tls.Certificate, implicitTLS bool) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if implicitTLS {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	s := &fakeSMTPServer{
+		listener:    listener,
+		tlsConfig:   tlsConfig,
+		implicitTLS: implicitTLS,
+		sessions:    make(chan smtpSession, 1),
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	session := smtpSession{tls: s.implicitTLS}
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ESMTP fake")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			text.PrintfLine("250-localhost")
+			if !session.tls {
+				text.PrintfLine("250-STARTTLS")
+			}
+			text.PrintfLine("250 AUTH PLAIN")
+		case "STARTTLS":
+			text.PrintfLine("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			text = textproto.NewConn(conn)
+			session.tls = true
+		case "AUTH":
+			mechanism, response, _ := strings.Cut(arg, " ")
+			decoded, err := base64.StdEncoding.DecodeString(response)
+			if mechanism != "PLAIN" || err != nil {
+				text.PrintfLine("535 Authentication failed")
+				continue
+			}
+			session.auth = strings.Split(string(decoded), "\x00")
+			text.PrintfLine("235 Authenticated")
+		case "MAIL":
+			session.from = strings.TrimSuffix(strings.TrimPrefix(arg, "FROM:<"), ">")
+			text.PrintfLine("250 OK")
+		case "RCPT":
+			session.to = append(session.to, strings.TrimSuffix(strings.TrimPrefix(arg, "TO:<"), ">"))
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 Go ahead")
+			if session.data, err = text.ReadDotBytes(); err != nil {
+				return
+			}
+			text.PrintfLine("250 Queued")
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			s.sessions <- session
+			return
+		default:
+			text.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+// newLocalhostCertificate returns a self-signed certificate for localhost.
+func newLocalhostCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, roots
+}
+
+func TestEmailChannelSend(t *testing.T) {
+	cert, roots := newLocalhostCertificate(t)
+
+	for _, security := range []string{config.SMTPStartTLS, config.SMTPTLS} {
+		t.Run(security, func(t *testing.T) {
+			server := startFakeSMTPServer(t, cert, security == config.SMTPTLS)
+
+			channel, err := newEmailChannel(config.ChannelConfig{
+				Name:     "oncall",
+				Type:     config.ChannelEmail,
+				Host:     "localhost",
+				Security: security,
+				Username: "monitor",
+				Password: "s3cret",
+				From:     "Uptime Monitor <monitor@example.test>",
+				To:       []string{"ops@example.test", "Dev Team <dev@example.test>"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			channel.dial = func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, server.listener.Addr().String())
+			}
+			channel.tlsConfig = &tls.Config{ServerName: "localhost", RootCAs: roots}
+
+			err = channel.Send(Event{
+				APIName:   "orders",
+				Type:      "down",
+				Severity:  "critical",
+				Message:   "Health check failed: connection refused",
+				Timestamp: time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+			})
+			if err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+
+			var session smtpSession
+			select {
+			case session = <-server.sessions:
+			case <-time.After(5 * time.Second):
+				t.Fatal("no SMTP session received")
+			}
+
+			if !session.tls {
+				t.Error("message sent without TLS")
+			}
+			if want := []string{"", "monitor", "s3cret"}; strings.Join(session.auth, ",") != strings.Join(want, ",") {
+				t.Errorf("AUTH PLAIN = %q, want %q", session.auth, want)
+			}
+			if session.from != "monitor@example.test" {
+				t.Errorf("MAIL FROM = %q, want monitor@example.test", session.from)
+			}
+			if want := "ops@example.test,dev@example.test"; strings.Join(session.to, ",") != want {
+				t.Errorf("RCPT TO = %q, want %s", session.to, want)
+			}
+
+			checkAlertMessage(t, session.data)
+		})
+	}
+}
+
+// checkAlertMessage checks the headers and both bodies of the message sent
+// for the "orders" down event.
+func checkAlertMessage(t *testing.T, data []byte) {
+	t.Helper()
+
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("parsing message: %v", err)
+	}
+
+	rawSubject := msg.Header.Get("Subject")
+	if !strings.HasPrefix(rawSubject, "=?utf-8?q?") {
+		t.Errorf("Subject = %q, want it Q-encoded", rawSubject)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(rawSubject)
+	if err != nil {
+		t.Fatalf("decoding subject: %v", err)
+	}
+	if want := "❌ orders API Status Change: down"; subject != want {
+		t.Errorf("Subject = %q, want %q", subject, want)
+	}
+	if to := msg.Header.Get("To"); to != "ops@example.test, Dev Team <dev@example.test>" {
+		t.Errorf("To = %q", to)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", msg.Header.Get("Content-Type"))
+	}
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	wantParts := []struct {
+		contentType string
+		contains    []string
+	}{
+		{"text/plain; charset=utf-8", []string{"Health check failed: connection refused", "Severity: critical", "Time:     2024-05-01 12:30:00 UTC"}},
+		{"text/html; charset=utf-8", []string{"<h2>❌ orders API Status Change</h2>", "Health check failed: connection refused", "#ff0000"}},
+	}
+	for _, want := range wantParts {
+		part, err := parts.NextPart()
+		if err != nil {
+			t.Fatalf("reading %s part: %v", want.contentType, err)
+		}
+		if got := part.Header.Get("Content-Type"); got != want.contentType {
+			t.Errorf("part Content-Type = %q, want %q", got, want.contentType)
+		}
+		// NextPart decodes quoted-printable bodies.
+		body, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, text := range want.contains {
+			if !bytes.Contains(body, []byte(text)) {
+				t.Errorf("%s part does not contain %q:\n%s", want.contentType, text, body)
+			}
+		}
+	}
+	if _, err := parts.NextPart(); err != io.EOF {
+		t.Errorf("extra part after text and html: %v", err)
+	}
+}
+
+func TestEmailChannelRequiresSTARTTLS(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		text := textproto.NewConn(conn)
+		text.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			if strings.HasPrefix(line, "EHLO") {
+				text.PrintfLine("250 localhost")
+			} else {
+				text.PrintfLine("221 Bye")
+				return
+			}
+		}
+	}()
+
+	channel, err := newEmailChannel(config.ChannelConfig{
+		Name: "oncall",
+		Type: config.ChannelEmail,
+		Host: "localhost",
+		From: "monitor@example.test",
+		To:   []string{"ops@example.test"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	channel.dial = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, listener.Addr().String())
+	}
+
+	err = channel.Send(Event{APIName: "orders", Type: "down", Timestamp: time.Now()})
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("Send = %v, want a STARTTLS error", err)
+	}
+}
@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+
+	"railway-api-uptime-monitor/internal/config"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the request body, keyed with
+// the channel's secret, as "sha256=<hex>".
+const SignatureHeader = "X-Uptime-Signature-256"
+
+// defaultWebhookTemplate sends the whole event as JSON.
+const defaultWebhookTemplate = `{{json .}}`
+
+// genericWebhook is an outbound webhook whose body is rendered from a
+// text/template executed with an Event.
+type genericWebhook struct {
+	url     string
+	method  string
+	headers map[string]string
+	body    *template.Template
+	secret  string
+	client  *http.Client
+}
+
+var templateFuncs = template.FuncMap{
+	// json encodes a value, so templates can embed strings in JSON bodies
+	// safely: {"text": {{json .Message}}}.
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+func newGenericWebhook(channelConfig config.ChannelConfig, client *http.Client) (*genericWebhook, error) {
+	text, err := templateText(channelConfig, defaultWebhookTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := template.New("webhook").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook template: %w", err)
+	}
+
+	method := strings.ToUpper(channelConfig.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	return &genericWebhook{
+		url:     channelConfig.URL,
+		method:  method,
+		headers: channelConfig.Headers,
+		body:    body,
+		secret:  channelConfig.Secret,
+		client:  client,
+	}, nil
+}
+
+// templateText returns the channel's body template, read from TemplateFile if
+// set, or fallback if the channel has none.
+func templateText(channelConfig config.ChannelConfig, fallback string) (string, error) {
+	text := channelConfig.Template
+	if channelConfig.TemplateFile != "" {
+		data, err := os.ReadFile(channelConfig.TemplateFile)
+		if err != nil {
+			return "", fmt.Errorf("reading template: %w", err)
+		}
+		text = string(data)
+	}
+	if text == "" {
+		text = fallback
+	}
+	return text, nil
+}
+
+func (w *genericWebhook) Send(event Event) error {
+	req, err := w.request(event)
+	if err != nil {
+		return fmt.Errorf("building webhook request: %w", err)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// request renders the body for event and signs it.
+func (w *genericWebhook) request(event Event) (*http.Request, error) {
+	var body bytes.Buffer
+	if err := w.body.Execute(&body, event); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(w.method, w.url, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Railway-API-Uptime-Monitor/1.0")
+	for key, value := range w.headers {
+		req.Header.Set(key, value)
+	}
+
+	if w.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(w.secret, body.Bytes()))
+	}
+
+	return req, nil
+}
+
+// Sign returns the signature header value of body: "sha256=" followed by the
+// hex HMAC-SHA256 of body keyed with secret. Receivers recompute it and
+// compare it with hmac.Equal.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,150 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"railway-api-uptime-monitor/internal/config"
+)
+
+// webhookRequest is what the test server received for one request.
+type webhookRequest struct {
+	method string
+	header http.Header
+	body   []byte
+}
+
+func startWebhookServer(t *testing.T) (*httptest.Server, chan webhookRequest) {
+	t.Helper()
+
+	requests := make(chan webhookRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests <- webhookRequest{method: r.Method, header: r.Header, body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func receiveWebhook(t *testing.T, requests chan webhookRequest) webhookRequest {
+	t.Helper()
+
+	select {
+	case req := <-requests:
+		return req
+	case <-time.After(5 * time.Second):
+		t.Fatal("no webhook request received")
+	}
+	return webhookRequest{}
+}
+
+var testEvent = Event{
+	APIName:   "orders",
+	Type:      "down",
+	Severity:  "critical",
+	Message:   `Health check failed: "connection refused"`,
+	Timestamp: time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+}
+
+func TestGenericWebhookDefaultBody(t *testing.T) {
+	server, requests := startWebhookServer(t)
+
+	channel, err := newGenericWebhook(config.ChannelConfig{
+		Name:   "ops",
+		Type:   config.ChannelWebhook,
+		URL:    server.URL,
+		Secret: "s3cret",
+	}, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := channel.Send(testEvent); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	req := receiveWebhook(t, requests)
+	if req.method != http.MethodPost {
+		t.Errorf("method = %s, want POST", req.method)
+	}
+	if got := req.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+
+	var got Event
+	if err := json.Unmarshal(req.body, &got); err != nil {
+		t.Fatalf("body is not the event as JSON: %v\n%s", err, req.body)
+	}
+	if got != testEvent {
+		t.Errorf("body = %+v, want %+v", got, testEvent)
+	}
+
+	signature := req.header.Get(SignatureHeader)
+	if !hmac.Equal([]byte(signature), []byte(Sign("s3cret", req.body))) {
+		t.Errorf("%s = %q does not match the body", SignatureHeader, signature)
+	}
+	if hmac.Equal([]byte(signature), []byte(Sign("other", req.body))) {
+		t.Errorf("%s matches a different secret", SignatureHeader)
+	}
+}
+
+func TestGenericWebhookTemplate(t *testing.T) {
+	server, requests := startWebhookServer(t)
+
+	channel, err := newGenericWebhook(config.ChannelConfig{
+		Name:     "pager",
+		Type:     config.ChannelWebhook,
+		URL:      server.URL,
+		Method:   "put",
+		Headers:  map[string]string{"Authorization": "Bearer token", "Content-Type": "application/vnd.pager+json"},
+		Template: `{"summary": {{json .Message}}, "source": "{{.APIName}}", "severity": "{{.Severity}}"}`,
+	}, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := channel.Send(testEvent); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	req := receiveWebhook(t, requests)
+	if req.method != http.MethodPut {
+		t.Errorf("method = %s, want PUT", req.method)
+	}
+	want := `{"summary": "Health check failed: \"connection refused\"", "source": "orders", "severity": "critical"}`
+	if string(req.body) != want {
+		t.Errorf("body = %s, want %s", req.body, want)
+	}
+	if got := req.header.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("Authorization = %q, want the custom header", got)
+	}
+	if got := req.header.Get("Content-Type"); got != "application/vnd.pager+json" {
+		t.Errorf("Content-Type = %q, want the custom header to override the default", got)
+	}
+	if got := req.header.Get(SignatureHeader); got != "" {
+		t.Errorf("%s = %q sent without a secret", SignatureHeader, got)
+	}
+}
+
+func TestGenericWebhookErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	channel, err := newGenericWebhook(config.ChannelConfig{URL: server.URL}, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := channel.Send(testEvent); err == nil {
+		t.Error("Send succeeded on a 502 response")
+	}
+}
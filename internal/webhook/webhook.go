@@ -3,17 +3,40 @@ package webhook
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"net/http"
 	"time"
 
 	"railway-api-uptime-monitor/internal/config"
+	"railway-api-uptime-monitor/internal/models"
 )
 
+// Channel delivers notifications to one destination, such as a Slack
+// webhook.
+type Channel interface {
+	Send(event Event) error
+}
+
+// Notifier is the registry of named notification channels. Monitors pick
+// the channels they notify by name.
 type Notifier struct {
-	config *config.Config
-	client *http.Client
+	channels map[string]Channel
+	names    []string
+}
+
+// Event is a notification and the alert and incident it is about. Templated
+// channels render their payloads from it.
+type Event struct {
+	APIName   string           `json:"api_name"`
+	Type      string           `json:"type"`
+	Severity  string           `json:"severity"`
+	Message   string           `json:"message"`
+	Timestamp time.Time        `json:"timestamp"`
+	Alert     *models.Alert    `json:"alert,omitempty"`
+	Incident  *models.Incident `json:"incident,omitempty"`
 }
 
 type SlackPayload struct {
@@ -58,31 +81,193 @@ type DiscordEmbedField struct {
 	Inline bool   `json:"inline"`
 }
 
-func NewNotifier(cfg *config.Config) *Notifier {
-	return &Notifier{
-		config: cfg,
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+// NewNotifier registers the channels enabled through environment variables,
+// named "slack", "discord" and "webhook", and those defined in
+// NOTIFICATION_CHANNELS_FILE. It fails if a channel definition is invalid.
+func NewNotifier(cfg *config.Config) (*Notifier, error) {
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
+	var channels []config.ChannelConfig
+	if cfg.EnableSlack && cfg.SlackWebhookURL != "" {
+		channels = append(channels, config.ChannelConfig{
+			Name: config.ChannelSlack,
+			Type: config.ChannelSlack,
+			URL:  cfg.SlackWebhookURL,
+		})
 	}
+	if cfg.EnableDiscord && cfg.DiscordWebhookURL != "" {
+		channels = append(channels, config.ChannelConfig{
+			Name: config.ChannelDiscord,
+			Type: config.ChannelDiscord,
+			URL:  cfg.DiscordWebhookURL,
+		})
+	}
+	if cfg.EnableWebhook && cfg.WebhookURL != "" {
+		channels = append(channels, config.ChannelConfig{
+			Name:         config.ChannelWebhook,
+			Type:         config.ChannelWebhook,
+			URL:          cfg.WebhookURL,
+			Method:       cfg.WebhookMethod,
+			Headers:      cfg.WebhookHeaders,
+			Template:     cfg.WebhookTemplate,
+			TemplateFile: cfg.WebhookTemplateFile,
+			Secret:       cfg.WebhookSecret,
+		})
+	}
+
+	if cfg.ChannelsFile != "" {
+		channelsConfig, err := config.LoadChannels(cfg.ChannelsFile)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return nil, fmt.Errorf("loading %s: %w", cfg.ChannelsFile, err)
+		default:
+			channels = append(channels, channelsConfig.Channels...)
+		}
+	}
+
+	n := &Notifier{channels: map[string]Channel{}}
+	for _, channelConfig := range channels {
+		channel, err := newChannel(channelConfig, client)
+		if err != nil {
+			return nil, fmt.Errorf("channel %q: %w", channelConfig.Name, err)
+		}
+		if err := n.Register(channelConfig.Name, channel); err != nil {
+			return nil, err
+		}
+	}
+
+	return n, nil
 }
 
-func (n *Notifier) SendAlert(apiName, alertType, message string) {
-	if n.config.EnableSlack && n.config.SlackWebhookURL != "" {
-		go n.sendSlackAlert(apiName, alertType, message)
+func newChannel(channelConfig config.ChannelConfig, client *http.Client) (Channel, error) {
+	channelConfig, err := channelConfig.ResolveSecrets()
+	if err != nil {
+		return nil, err
+	}
+
+	switch channelConfig.Type {
+	case config.ChannelSlack, config.ChannelDiscord, config.ChannelWebhook:
+		if channelConfig.URL == "" {
+			return nil, errors.New("url is required")
+		}
+	}
+
+	switch channelConfig.Type {
+	case config.ChannelSlack:
+		return &slackChannel{url: channelConfig.URL, client: client}, nil
+	case config.ChannelDiscord:
+		return &discordChannel{url: channelConfig.URL, client: client}, nil
+	case config.ChannelWebhook:
+		return newGenericWebhook(channelConfig, client)
+	case config.ChannelEmail:
+		return newEmailChannel(channelConfig)
+	}
+
+	return nil, fmt.Errorf("unknown channel type %q", channelConfig.Type)
+}
+
+// Register adds a channel under name. Names must be unique.
+func (n *Notifier) Register(name string, channel Channel) error {
+	if name == "" {
+		return errors.New("channel name is required")
 	}
+	if _, ok := n.channels[name]; ok {
+		return fmt.Errorf("duplicate channel name %q", name)
+	}
+
+	n.channels[name] = channel
+	n.names = append(n.names, name)
+	return nil
+}
+
+// Channels returns the names of the registered channels in registration
+// order.
+func (n *Notifier) Channels() []string {
+	return append([]string{}, n.names...)
+}
 
-	if n.config.EnableDiscord && n.config.DiscordWebhookURL != "" {
-		go n.sendDiscordAlert(apiName, alertType, message)
+// CheckRules reports the first rule naming a channel that is not
+// registered.
+func (n *Notifier) CheckRules(rules []config.NotifyRule) error {
+	for _, rule := range rules {
+		if _, ok := n.channels[rule.Channel]; !ok {
+			return fmt.Errorf("unknown notification channel %q", rule.Channel)
+		}
 	}
+	return nil
 }
 
-func (n *Notifier) sendSlackAlert(apiName, alertType, message string) {
+// SendAlert sends event to the channels selected by a monitor's notify
+// rules, or to every channel if it has none.
+func (n *Notifier) SendAlert(event Event, rules []config.NotifyRule) {
+	for _, name := range n.targets(event, rules) {
+		channel, ok := n.channels[name]
+		if !ok {
+			log.Printf("Unknown notification channel %q for %s", name, event.APIName)
+			continue
+		}
+		go n.send(name, channel, event)
+	}
+}
+
+func (n *Notifier) send(name string, channel Channel, event Event) {
+	if err := channel.Send(event); err != nil {
+		log.Printf("Error sending %s notification: %v", name, err)
+		return
+	}
+	log.Printf("Notification sent to %s for %s: %s", name, event.APIName, event.Type)
+}
+
+// targets returns the names of the channels an event goes to. A recovery or
+// manual resolve is matched by the alert it resolves, so it reaches the
+// channels that were told about the alert.
+func (n *Notifier) targets(event Event, rules []config.NotifyRule) []string {
+	if len(rules) == 0 {
+		return n.names
+	}
+
+	alertType, severity := event.Type, event.Severity
+	if (event.Type == "up" || event.Type == "incident_resolved") && event.Alert != nil {
+		alertType, severity = event.Alert.Type, event.Alert.Severity
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	for _, rule := range rules {
+		if seen[rule.Channel] || !rule.Matches(alertType, severity) {
+			continue
+		}
+		seen[rule.Channel] = true
+		names = append(names, rule.Channel)
+	}
+	return names
+}
+
+// slackChannel posts to a Slack incoming webhook.
+type slackChannel struct {
+	url    string
+	client *http.Client
+}
+
+func (c *slackChannel) Send(event Event) error {
+	return c.sendSlackAlert(event.APIName, event.Type, event.Message)
+}
+
+func (c *slackChannel) sendSlackAlert(apiName, alertType, message string) error {
 	color := "#ff0000" // Red for down
 	emoji := ":x:"
 	if alertType == "up" {
 		color = "#00ff00" // Green for up
 		emoji = ":white_check_mark:"
+	} else if alertType == "degraded" {
+		color = "#ffcc00" // Yellow for slow responses
+		emoji = ":warning:"
+	} else if alertType == "incident_resolved" {
+		color = "#439fe0" // Blue for incidents resolved by hand
+		emoji = ":information_source:"
 	}
 
 	payload := SlackPayload{
@@ -118,28 +303,39 @@ func (n *Notifier) sendSlackAlert(apiName, alertType, message string) {
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		log.Printf("Error marshaling Slack payload: %v", err)
-		return
+		return fmt.Errorf("marshaling Slack payload: %w", err)
 	}
 
-	resp, err := n.client.Post(n.config.SlackWebhookURL, "application/json", bytes.NewBuffer(jsonData))
+	resp, err := c.client.Post(c.url, "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
-		log.Printf("Error sending Slack notification: %v", err)
-		return
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("Slack webhook returned status: %d", resp.StatusCode)
-	} else {
-		log.Printf("Slack notification sent for %s: %s", apiName, alertType)
+		return fmt.Errorf("Slack webhook returned status: %d", resp.StatusCode)
 	}
+	return nil
 }
 
-func (n *Notifier) sendDiscordAlert(apiName, alertType, message string) {
+// discordChannel posts to a Discord webhook.
+type discordChannel struct {
+	url    string
+	client *http.Client
+}
+
+func (c *discordChannel) Send(event Event) error {
+	return c.sendDiscordAlert(event.APIName, event.Type, event.Message)
+}
+
+func (c *discordChannel) sendDiscordAlert(apiName, alertType, message string) error {
 	color := 16711680 // Red for down
 	if alertType == "up" {
 		color = 65280 // Green for up
+	} else if alertType == "degraded" {
+		color = 16763904 // Yellow for slow responses
+	} else if alertType == "incident_resolved" {
+		color = 4431840 // Blue for incidents resolved by hand
 	}
 
 	payload := DiscordPayload{
@@ -168,20 +364,17 @@ func (n *Notifier) sendDiscordAlert(apiName, alertType, message string) {
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		log.Printf("Error marshaling Discord payload: %v", err)
-		return
+		return fmt.Errorf("marshaling Discord payload: %w", err)
 	}
 
-	resp, err := n.client.Post(n.config.DiscordWebhookURL, "application/json", bytes.NewBuffer(jsonData))
+	resp, err := c.client.Post(c.url, "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
-		log.Printf("Error sending Discord notification: %v", err)
-		return
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		log.Printf("Discord webhook returned status: %d", resp.StatusCode)
-	} else {
-		log.Printf("Discord notification sent for %s: %s", apiName, alertType)
+		return fmt.Errorf("Discord webhook returned status: %d", resp.StatusCode)
 	}
+	return nil
 }
@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"reflect"
+	"testing"
+
+	"railway-api-uptime-monitor/internal/config"
+	"railway-api-uptime-monitor/internal/models"
+)
+
+// nopChannel is a channel that drops every event.
+type nopChannel struct{}
+
+func (nopChannel) Send(Event) error { return nil }
+
+func TestNotifierTargets(t *testing.T) {
+	n := &Notifier{channels: map[string]Channel{}}
+	for _, name := range []string{"ops", "team", "pager"} {
+		if err := n.Register(name, nopChannel{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rules := []config.NotifyRule{
+		{Channel: "team"},
+		{Channel: "pager", Severities: []string{"critical"}, Types: []string{"down", "timeout"}},
+		{Channel: "ops", Types: []string{"certificate"}},
+		{Channel: "team", Severities: []string{"critical"}},
+	}
+	downAlert := &models.Alert{Type: "down", Severity: "critical"}
+	degradedAlert := &models.Alert{Type: "degraded", Severity: "warning"}
+
+	tests := []struct {
+		name  string
+		event Event
+		rules []config.NotifyRule
+		want  []string
+	}{
+		{
+			name:  "no rules",
+			event: Event{Type: "degraded", Severity: "warning"},
+			want:  []string{"ops", "team", "pager"},
+		},
+		{
+			name:  "severity and type match",
+			event: Event{Type: "down", Severity: "critical"},
+			rules: rules,
+			want:  []string{"team", "pager"},
+		},
+		{
+			name:  "type filtered",
+			event: Event{Type: "certificate", Severity: "critical"},
+			rules: rules,
+			want:  []string{"team", "ops"},
+		},
+		{
+			name:  "severity filtered",
+			event: Event{Type: "timeout", Severity: "warning"},
+			rules: rules,
+			want:  []string{"team"},
+		},
+		{
+			name:  "no rule matches",
+			event: Event{Type: "keyword", Severity: "info"},
+			rules: rules[1:3],
+			want:  nil,
+		},
+		{
+			name:  "up follows the alert it resolves",
+			event: Event{Type: "up", Severity: "info", Alert: downAlert},
+			rules: rules,
+			want:  []string{"team", "pager"},
+		},
+		{
+			name:  "up after a degraded alert",
+			event: Event{Type: "up", Severity: "info", Alert: degradedAlert},
+			rules: rules,
+			want:  []string{"team"},
+		},
+		{
+			name:  "up without an alert",
+			event: Event{Type: "up", Severity: "info"},
+			rules: rules,
+			want:  []string{"team"},
+		},
+		{
+			name:  "manual resolve follows the alert it resolves",
+			event: Event{Type: "incident_resolved", Severity: "info", Alert: downAlert},
+			rules: rules,
+			want:  []string{"team", "pager"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := n.targets(tt.event, tt.rules); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("targets = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
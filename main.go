@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"log"
 	"net/http"
 	"os"
@@ -16,7 +19,6 @@ import (
 	"railway-api-uptime-monitor/internal/webhook"
 
 	"github.com/joho/godotenv"
-	"github.com/robfig/cron/v3"
 )
 
 func main() {
@@ -36,24 +38,30 @@ func main() {
 	defer db.Disconnect()
 
 	// Initialize webhook notifier
-	notifier := webhook.NewNotifier(cfg)
+	notifier, err := webhook.NewNotifier(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize notifier: %v", err)
+	}
+
+	// Prepare the monitors collection and import the optional seed file
+	if err := initMonitors(db, notifier, cfg.MonitorsSeedFile); err != nil {
+		log.Fatalf("Failed to initialize monitors: %v", err)
+	}
 
 	// Initialize monitor
 	apiMonitor := monitor.New(db, notifier, cfg)
 
-	// Set up cron job for monitoring
-	c := cron.New()
-	_, err = c.AddFunc(cfg.CheckInterval, func() {
-		log.Println("Running scheduled API health checks...")
-		apiMonitor.CheckAllAPIs()
-	})
-	if err != nil {
-		log.Fatalf("Failed to set up cron job: %v", err)
+	// Validate the default check interval used by monitors without their own
+	if _, err := config.ParseInterval(cfg.CheckInterval); err != nil {
+		log.Fatalf("Invalid CHECK_INTERVAL: %v", err)
 	}
-	c.Start()
+
+	// Start the per-monitor scheduler
+	scheduler := monitor.NewScheduler(apiMonitor)
+	scheduler.Start()
 
 	// Initialize and start web server
-	srv := server.New(db, cfg)
+	srv := server.New(db, cfg, apiMonitor)
 
 	// Graceful shutdown
 	go func() {
@@ -68,8 +76,8 @@ func main() {
 	<-quit
 	log.Println("Shutting down server...")
 
-	// Stop cron jobs
-	c.Stop()
+	// Stop scheduling checks
+	scheduler.Stop()
 
 	// Shutdown server
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -80,3 +88,51 @@ func main() {
 
 	log.Println("Server exiting")
 }
+
+func initMonitors(db *database.Database, notifier *webhook.Notifier, seedFile string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := db.EnsureMonitorIndexes(ctx); err != nil {
+		return err
+	}
+
+	resolved, err := db.ResolveLegacyAlerts(ctx)
+	if err != nil {
+		return err
+	}
+	if resolved > 0 {
+		log.Printf("Resolved %d legacy alerts", resolved)
+	}
+
+	if seedFile == "" {
+		return nil
+	}
+
+	apisConfig, err := config.LoadAPIs(seedFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Printf("No monitor seed file at %s, skipping import", seedFile)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for i := range apisConfig.APIs {
+		apisConfig.APIs[i].ApplyDefaults()
+		if err := apisConfig.APIs[i].Validate(); err != nil {
+			return fmt.Errorf("invalid monitor %q in %s: %v", apisConfig.APIs[i].Name, seedFile, err)
+		}
+		if err := notifier.CheckRules(apisConfig.APIs[i].Notify); err != nil {
+			return fmt.Errorf("invalid monitor %q in %s: %v", apisConfig.APIs[i].Name, seedFile, err)
+		}
+	}
+
+	imported, err := db.SeedMonitors(ctx, apisConfig.APIs)
+	if err != nil {
+		return err
+	}
+	log.Printf("Imported %d monitors from %s", imported, seedFile)
+
+	return nil
+}